@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/haileyok/penelope/penelope"
+	"github.com/urfave/cli/v2"
+)
+
+var listConversations = func(cmd *cli.Context) error {
+	p, err := penelope.NewOffline(argsFromContext(cmd, newLogger(cmd, os.Stderr)))
+	if err != nil {
+		return err
+	}
+
+	q := penelope.ConversationQuery{
+		Did:   cmd.String("did"),
+		Limit: cmd.Int("limit"),
+	}
+	if t := cmd.Timestamp("since"); t != nil {
+		q.Since = *t
+	}
+	if t := cmd.Timestamp("until"); t != nil {
+		q.Until = *t
+	}
+
+	convs, err := p.ListConversations(q)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	for _, c := range convs {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
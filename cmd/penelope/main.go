@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/haileyok/penelope/penelope"
 	_ "github.com/joho/godotenv/autoload"
@@ -31,19 +35,16 @@ func main() {
 				EnvVars: []string{"PENELOPE_DEBUG"},
 			},
 			&cli.StringFlag{
-				Name:     "cursor-file",
-				EnvVars:  []string{"PENELOPE_CURSOR_FILE"},
-				Required: true,
+				Name:    "cursor-file",
+				EnvVars: []string{"PENELOPE_CURSOR_FILE"},
 			},
 			&cli.StringFlag{
-				Name:     "clickhouse-addr",
-				EnvVars:  []string{"PENELOPE_CLICKHOUSE_ADDR"},
-				Required: true,
+				Name:    "clickhouse-addr",
+				EnvVars: []string{"PENELOPE_CLICKHOUSE_ADDR"},
 			},
 			&cli.StringFlag{
-				Name:     "clickhouse-database",
-				EnvVars:  []string{"PENELOPE_CLICKHOUSE_DATABASE"},
-				Required: true,
+				Name:    "clickhouse-database",
+				EnvVars: []string{"PENELOPE_CLICKHOUSE_DATABASE"},
 			},
 			&cli.StringFlag{
 				Name:    "clickhouse-user",
@@ -51,68 +52,184 @@ func main() {
 				Value:   "default",
 			},
 			&cli.StringFlag{
-				Name:     "clickhouse-pass",
-				EnvVars:  []string{"PENELOPE_CLICKHOUSE_PASS"},
-				Required: true,
+				Name:    "clickhouse-pass",
+				EnvVars: []string{"PENELOPE_CLICKHOUSE_PASS"},
 			},
 			&cli.StringFlag{
-				Name:     "bot-did",
-				EnvVars:  []string{"PENELOPE_BOT_DID"},
-				Required: true,
+				Name:    "bot-did",
+				EnvVars: []string{"PENELOPE_BOT_DID"},
 			},
 			&cli.StringFlag{
-				Name:     "bot-identifier",
-				EnvVars:  []string{"PENELOPE_BOT_IDENTIFIER"},
-				Required: true,
+				Name:    "bot-identifier",
+				EnvVars: []string{"PENELOPE_BOT_IDENTIFIER"},
 			},
 			&cli.StringFlag{
-				Name:     "bot-password",
-				EnvVars:  []string{"PENELOPE_BOT_PASSWORD"},
-				Required: true,
+				Name:    "bot-password",
+				EnvVars: []string{"PENELOPE_BOT_PASSWORD"},
 			},
 			&cli.StringFlag{
-				Name:     "bot-pds-host",
-				EnvVars:  []string{"PENELOPE_BOT_PDS_HOST"},
-				Required: true,
+				Name:    "bot-pds-host",
+				EnvVars: []string{"PENELOPE_BOT_PDS_HOST"},
 			},
 			&cli.StringSliceFlag{
-				Name:     "bot-admins",
-				EnvVars:  []string{"PENELOPE_BOT_ADMINS"},
-				Required: true,
+				Name:    "bot-admins",
+				EnvVars: []string{"PENELOPE_BOT_ADMINS"},
 			},
 			&cli.StringFlag{
-				Name:     "letta-host",
-				EnvVars:  []string{"PENELOPE_LETTA_HOST"},
-				Required: true,
+				Name:    "letta-host",
+				EnvVars: []string{"PENELOPE_LETTA_HOST"},
 			},
 			&cli.StringFlag{
-				Name:     "letta-api-key",
-				EnvVars:  []string{"PENELOPE_LETTA_API_KEY"},
-				Required: true,
+				Name:    "letta-api-key",
+				EnvVars: []string{"PENELOPE_LETTA_API_KEY"},
 			},
 			&cli.StringFlag{
-				Name:     "letta-agent-name",
-				EnvVars:  []string{"PENELOPE_LETTA_AGENT_NAME"},
-				Required: true,
+				Name:    "letta-agent-name",
+				EnvVars: []string{"PENELOPE_LETTA_AGENT_NAME"},
 			},
 			&cli.StringSliceFlag{
-				Name:     "ignore-dids",
-				EnvVars:  []string{"PENELOPE_IGNORE_DIDS"},
-				Required: true,
+				Name:    "ignore-dids",
+				EnvVars: []string{"PENELOPE_IGNORE_DIDS"},
 			},
 			&cli.BoolFlag{
 				Name:    "admin-only",
 				EnvVars: []string{"PENELOPE_ADMIN_ONLY"},
 			},
 			&cli.StringFlag{
-				Name:     "api-key",
-				EnvVars:  []string{"PENELOPE_API_KEY"},
-				Required: true,
+				Name:    "api-key",
+				EnvVars: []string{"PENELOPE_API_KEY"},
 			},
 			&cli.StringFlag{
-				Name:     "addr",
-				EnvVars:  []string{"PENELOPE_ADDR"},
-				Required: true,
+				Name:    "addr",
+				EnvVars: []string{"PENELOPE_ADDR"},
+			},
+			&cli.IntFlag{
+				Name:    "user-rate-limit-burst",
+				Usage:   "number of replies a single user can trigger before being rate limited. 0 disables the limit",
+				EnvVars: []string{"PENELOPE_USER_RATE_LIMIT_BURST"},
+				Value:   5,
+			},
+			&cli.DurationFlag{
+				Name:    "user-rate-limit-refill",
+				Usage:   "how long it takes for a single user to regain one reply",
+				EnvVars: []string{"PENELOPE_USER_RATE_LIMIT_REFILL"},
+				Value:   2 * time.Minute,
+			},
+			&cli.IntFlag{
+				Name:    "global-rate-limit-burst",
+				Usage:   "number of replies the bot can make before being rate limited. 0 disables the limit",
+				EnvVars: []string{"PENELOPE_GLOBAL_RATE_LIMIT_BURST"},
+				Value:   60,
+			},
+			&cli.DurationFlag{
+				Name:    "global-rate-limit-refill",
+				Usage:   "how long it takes for the bot to regain one reply",
+				EnvVars: []string{"PENELOPE_GLOBAL_RATE_LIMIT_REFILL"},
+				Value:   10 * time.Second,
+			},
+			&cli.IntFlag{
+				Name:    "max-thread-depth",
+				Usage:   "maximum depth of a thread that the bot will reply in. 0 disables the limit",
+				EnvVars: []string{"PENELOPE_MAX_THREAD_DEPTH"},
+				Value:   40,
+			},
+			&cli.IntFlag{
+				Name:    "max-bot-exchanges",
+				Usage:   "maximum number of back and forth replies the bot will have with another automated account. 0 disables the limit",
+				EnvVars: []string{"PENELOPE_MAX_BOT_EXCHANGES"},
+				Value:   3,
+			},
+			&cli.DurationFlag{
+				Name:    "bot-reply-cadence",
+				Usage:   "replies to the bot that arrive faster than this are considered automated",
+				EnvVars: []string{"PENELOPE_BOT_REPLY_CADENCE"},
+				Value:   10 * time.Second,
+			},
+			&cli.StringSliceFlag{
+				Name:    "label-policy",
+				Usage:   "overrides for how to handle moderation labels, in the form of label=action. actions are reply, reply-without-memory and ignore",
+				EnvVars: []string{"PENELOPE_LABEL_POLICY"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "labelers",
+				Usage:   "dids of labeler services to subscribe to in addition to the appview's defaults",
+				EnvVars: []string{"PENELOPE_LABELERS"},
+			},
+			&cli.StringFlag{
+				Name:    "admin-api-key",
+				Usage:   "api key for the /admin api. the admin api is disabled if this is not set",
+				EnvVars: []string{"PENELOPE_ADMIN_API_KEY"},
+			},
+			&cli.StringFlag{
+				Name:    "ignore-list-uri",
+				Usage:   "at-uri of an app.bsky.graph.list whose members will be ignored",
+				EnvVars: []string{"PENELOPE_IGNORE_LIST_URI"},
+			},
+			&cli.StringFlag{
+				Name:    "admin-list-uri",
+				Usage:   "at-uri of an app.bsky.graph.list whose members will be bot admins",
+				EnvVars: []string{"PENELOPE_ADMIN_LIST_URI"},
+			},
+			&cli.DurationFlag{
+				Name:    "list-sync-interval",
+				Usage:   "how often to sync the ignore and admin lists",
+				EnvVars: []string{"PENELOPE_LIST_SYNC_INTERVAL"},
+				Value:   10 * time.Minute,
+			},
+			&cli.BoolFlag{
+				Name:    "enable-dms",
+				Usage:   "read and reply to direct messages sent to the bot. requires an app password with dm access",
+				EnvVars: []string{"PENELOPE_ENABLE_DMS"},
+			},
+			&cli.DurationFlag{
+				Name:    "dm-poll-interval",
+				Usage:   "how often to check for new direct messages",
+				EnvVars: []string{"PENELOPE_DM_POLL_INTERVAL"},
+				Value:   10 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:    "db-path",
+				EnvVars: []string{"PENELOPE_DB_PATH"},
+				Value:   "penelope.db",
+			},
+			&cli.DurationFlag{
+				Name:    "conversation-retention",
+				Usage:   "how long to keep conversation logs for. 0 keeps them forever",
+				EnvVars: []string{"PENELOPE_CONVERSATION_RETENTION"},
+				Value:   90 * 24 * time.Hour,
+			},
+			&cli.BoolFlag{
+				Name:    "lazy-memory-migration",
+				Usage:   "migrate a user's legacy memories the first time they talk to the bot. can be disabled once `memory migrate` has completed",
+				EnvVars: []string{"PENELOPE_LAZY_MEMORY_MIGRATION"},
+				Value:   true,
+			},
+			&cli.DurationFlag{
+				Name:    "block-monitor-interval",
+				Usage:   "how often to check how full user memory blocks are. 0 disables the check",
+				EnvVars: []string{"PENELOPE_BLOCK_MONITOR_INTERVAL"},
+				Value:   6 * time.Hour,
+			},
+			&cli.Float64Flag{
+				Name:    "block-compaction-threshold",
+				Usage:   "fill ratio at which a user memory block gets compacted. 0 disables compaction",
+				EnvVars: []string{"PENELOPE_BLOCK_COMPACTION_THRESHOLD"},
+				Value:   0.8,
+			},
+			&cli.StringFlag{
+				Name:    "standard-site-url",
+				Usage:   "base url of the bot's standard.site publication. enables publishing site.standard.document posts",
+				EnvVars: []string{"PENELOPE_STANDARD_SITE_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "standard-site-publication",
+				Usage:   "at-uri of the bot's site.standard.publication record. defaults to the standard-site-url",
+				EnvVars: []string{"PENELOPE_STANDARD_SITE_PUBLICATION"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "social-action-limits",
+				Usage:   "overrides for how many times per day the agent may take each social action, in the form of action=N. actions are like, unlike, repost, quote, follow and unfollow. 0 disables an action",
+				EnvVars: []string{"PENELOPE_SOCIAL_ACTION_LIMITS"},
 			},
 		},
 		Commands: cli.Commands{
@@ -120,28 +237,157 @@ func main() {
 				Name:   "run",
 				Action: run,
 			},
+			&cli.Command{
+				Name:  "conversations",
+				Usage: "print logged conversations as jsonl",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "did",
+						Usage: "only show conversations with this user",
+					},
+					&cli.TimestampFlag{
+						Name:   "since",
+						Layout: time.DateOnly,
+					},
+					&cli.TimestampFlag{
+						Name:   "until",
+						Layout: time.DateOnly,
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Value: 100,
+					},
+				},
+				Action: listConversations,
+			},
+			&cli.Command{
+				Name:  "memory",
+				Usage: "manage what the bot remembers about users",
+				Subcommands: cli.Commands{
+					&cli.Command{
+						Name:  "export",
+						Usage: "export the memory of every user as jsonl",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "out",
+								Usage: "file to write the export to. defaults to stdout",
+								Value: "-",
+							},
+						},
+						Action: exportMemory,
+					},
+					&cli.Command{
+						Name:  "migrate",
+						Usage: "migrate legacy memories into letta blocks. the bot should not be running while this is, since both use the same agent",
+						Flags: []cli.Flag{
+							&cli.IntFlag{
+								Name:  "limit",
+								Usage: "maximum number of users to migrate in this run. 0 migrates everyone",
+							},
+						},
+						Action: migrateMemory,
+					},
+				},
+			},
 		},
 		ErrWriter: os.Stderr,
 	}
 
-	app.Run(os.Args)
+	if err := app.Run(os.Args); err != nil {
+		slog.Error("penelope exited with an error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runFlags are the flags that only the run command needs. They aren't marked as required so that the offline commands
+// can be used without them.
+var runFlags = []string{
+	"cursor-file",
+	"clickhouse-addr",
+	"clickhouse-database",
+	"clickhouse-pass",
+	"bot-did",
+	"bot-identifier",
+	"bot-password",
+	"bot-pds-host",
+	"bot-admins",
+	"letta-host",
+	"letta-api-key",
+	"letta-agent-name",
+	"ignore-dids",
+	"api-key",
+	"addr",
+}
+
+// lettaFlags are the flags needed by the offline commands that talk to letta
+var lettaFlags = []string{
+	"letta-host",
+	"letta-api-key",
+	"letta-agent-name",
+}
+
+// requireFlags returns an error listing any of the given flags that were not set
+func requireFlags(cmd *cli.Context, names ...string) error {
+	var missing []string
+	for _, n := range names {
+		if !cmd.IsSet(n) {
+			missing = append(missing, n)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("required flags %q not set", strings.Join(missing, ", "))
+	}
+
+	return nil
 }
 
 var run = func(cmd *cli.Context) error {
+	if err := requireFlags(cmd, runFlags...); err != nil {
+		return err
+	}
+
 	ctx := cmd.Context
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	l := newLogger(cmd, os.Stdout)
+
+	p, err := penelope.New(ctx, argsFromContext(cmd, l))
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		exitSignals := make(chan os.Signal, 1)
+		signal.Notify(exitSignals, syscall.SIGINT, syscall.SIGTERM)
+
+		sig := <-exitSignals
+
+		l.Info("received os exit signal", "signal", sig)
+		cancel()
+	}()
+
+	if err := p.Run(ctx); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+func newLogger(cmd *cli.Context, w io.Writer) *slog.Logger {
 	level := slog.LevelInfo
 	if cmd.Bool("debug") {
 		level = slog.LevelDebug
 	}
 
-	l := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
 		Level: level,
 	}))
+}
 
-	p, err := penelope.New(ctx, &penelope.Args{
+func argsFromContext(cmd *cli.Context, l *slog.Logger) *penelope.Args {
+	return &penelope.Args{
 		Logger:             l,
 		RelayHost:          cmd.String("relay-host"),
 		MetricsAddr:        cmd.String("metrics-addr"),
@@ -162,24 +408,37 @@ var run = func(cmd *cli.Context) error {
 		AdminOnly:          cmd.Bool("admin-only"),
 		ApiKey:             cmd.String("api-key"),
 		Addr:               cmd.String("addr"),
-	})
-	if err != nil {
-		panic(err)
-	}
 
-	go func() {
-		exitSignals := make(chan os.Signal, 1)
-		signal.Notify(exitSignals, syscall.SIGINT, syscall.SIGTERM)
+		UserRateLimitBurst:    cmd.Int("user-rate-limit-burst"),
+		UserRateLimitRefill:   cmd.Duration("user-rate-limit-refill"),
+		GlobalRateLimitBurst:  cmd.Int("global-rate-limit-burst"),
+		GlobalRateLimitRefill: cmd.Duration("global-rate-limit-refill"),
 
-		sig := <-exitSignals
+		MaxThreadDepth:  cmd.Int("max-thread-depth"),
+		MaxBotExchanges: cmd.Int("max-bot-exchanges"),
+		BotReplyCadence: cmd.Duration("bot-reply-cadence"),
 
-		l.Info("received os exit signal", "signal", sig)
-		cancel()
-	}()
+		LabelPolicy: cmd.StringSlice("label-policy"),
+		Labelers:    cmd.StringSlice("labelers"),
 
-	if err := p.Run(ctx); err != nil {
-		panic(err)
-	}
+		AdminApiKey:      cmd.String("admin-api-key"),
+		IgnoreListUri:    cmd.String("ignore-list-uri"),
+		AdminListUri:     cmd.String("admin-list-uri"),
+		ListSyncInterval: cmd.Duration("list-sync-interval"),
 
-	return nil
+		EnableDms:      cmd.Bool("enable-dms"),
+		DmPollInterval: cmd.Duration("dm-poll-interval"),
+
+		DbPath:                cmd.String("db-path"),
+		ConversationRetention: cmd.Duration("conversation-retention"),
+		LazyMemoryMigration:   cmd.Bool("lazy-memory-migration"),
+
+		BlockMonitorInterval:     cmd.Duration("block-monitor-interval"),
+		BlockCompactionThreshold: cmd.Float64("block-compaction-threshold"),
+
+		StandardSitePublication: cmd.String("standard-site-publication"),
+		StandardSiteUrl:         cmd.String("standard-site-url"),
+
+		SocialActionLimits: cmd.StringSlice("social-action-limits"),
+	}
 }
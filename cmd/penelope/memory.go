@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/haileyok/penelope/penelope"
+	"github.com/urfave/cli/v2"
+)
+
+var exportMemory = func(cmd *cli.Context) error {
+	if err := requireFlags(cmd, lettaFlags...); err != nil {
+		return err
+	}
+
+	ctx := cmd.Context
+	l := newLogger(cmd, os.Stderr)
+
+	p, err := penelope.NewOffline(argsFromContext(cmd, l))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if out := cmd.String("out"); out != "" && out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	dids, err := p.ExportedDids()
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	enc := json.NewEncoder(w)
+	for i, did := range dids {
+		export, err := p.ExportUserMemory(ctx, did)
+		if err != nil {
+			return fmt.Errorf("failed to export memory for %s: %w", did, err)
+		}
+		if err := enc.Encode(export); err != nil {
+			return err
+		}
+		l.Info("exported memory", "did", did, "progress", fmt.Sprintf("%d/%d", i+1, len(dids)))
+	}
+
+	return nil
+}
+
+var migrateMemory = func(cmd *cli.Context) error {
+	if err := requireFlags(cmd, lettaFlags...); err != nil {
+		return err
+	}
+
+	ctx := cmd.Context
+	l := newLogger(cmd, os.Stderr)
+
+	p, err := penelope.NewOffline(argsFromContext(cmd, l))
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	if err := p.MigrateLegacyMemories(ctx, cmd.Int("limit"), func(mp penelope.MigrationProgress) {
+		progress := fmt.Sprintf("%d/%d", mp.Done, mp.Total)
+		switch {
+		case mp.Err != nil:
+			failed++
+			l.Error("failed to migrate memories", "did", mp.Did, "progress", progress, "error", mp.Err)
+		case mp.Skipped:
+			l.Info("user already has a block, marked as migrated", "did", mp.Did, "progress", progress)
+		default:
+			l.Info("migrated memories", "did", mp.Did, "progress", progress)
+		}
+	}); err != nil {
+		return err
+	}
+
+	remaining, err := p.UnmigratedMemoryUsers()
+	if err != nil {
+		return err
+	}
+
+	if remaining == 0 {
+		l.Info("all legacy memories have been migrated, lazy migration can now be disabled with --lazy-memory-migration=false")
+	} else {
+		l.Info("migration run finished", "failed", failed, "remaining", remaining)
+	}
+
+	return nil
+}
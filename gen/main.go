@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/haileyok/penelope/penelope"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func main() {
+	genCfg := cbg.Gen{
+		MaxStringLength: 1_000_000,
+	}
+
+	if err := genCfg.WriteMapEncodersToFile("penelope/cbor_gen.go", "penelope",
+		penelope.WhitewindRecord{},
+		penelope.WhitewindBlobMetadata{},
+		penelope.StandardSiteDocument{},
+	); err != nil {
+		panic(err)
+	}
+}
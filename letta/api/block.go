@@ -13,6 +13,17 @@ type CreateBlockResult struct {
 	ID    *string `json:"id,omitempty"`
 }
 
+type Block struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Limit int    `json:"limit"`
+}
+
+type UpdateBlockInput struct {
+	Value string `json:"value"`
+}
+
 type AttachBlockInput struct {
 	AgentID string `json:"agent_id"`
 	BlockID string `json:"block_id"`
@@ -6,10 +6,21 @@ type UpsertIdentityInput struct {
 	IdentityType  string             `json:"identity_type"`
 	Properties    []IdentityProperty `json:"properties"`
 	AgentIDs      []string           `json:"agent_ids"`
+	BlockIDs      []string           `json:"block_ids,omitempty"`
 }
 
 type IdentityProperty struct {
 	Key   string `json:"key"`
 	Value any    `json:"value"`
-	Type  string `json:"string"`
+	Type  string `json:"type"`
+}
+
+type Identity struct {
+	ID            string             `json:"id"`
+	IdentifierKey string             `json:"identifier_key"`
+	Name          string             `json:"name"`
+	IdentityType  string             `json:"identity_type"`
+	Properties    []IdentityProperty `json:"properties"`
+	AgentIDs      []string           `json:"agent_ids"`
+	BlockIDs      []string           `json:"block_ids"`
 }
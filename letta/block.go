@@ -40,8 +40,63 @@ func (c *Client) CreateBlock(ctx context.Context, input api.CreateBlockInput) (*
 	return &result, nil
 }
 
+func (c *Client) GetBlock(ctx context.Context, blockId string) (*api.Block, error) {
+	req, err := c.CreateGetRequest(ctx, "/v1/blocks/"+blockId)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %w", ErrRequest, err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrResponse, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: status %d", ErrBadStatusCode, resp.StatusCode)
+	}
+
+	var result api.Block
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJsonUnmarshal, err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) UpdateBlock(ctx context.Context, blockId string, input api.UpdateBlockInput) (*api.Block, error) {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJsonMarshal, err)
+	}
+
+	req, err := c.CreatePatchRequest(ctx, "/v1/blocks/"+blockId, b)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %w", ErrRequest, err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrResponse, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: status %d", ErrBadStatusCode, resp.StatusCode)
+	}
+
+	var result api.Block
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJsonUnmarshal, err)
+	}
+
+	return &result, nil
+}
+
 func (c *Client) AttachBlock(ctx context.Context, blockId string) error {
-	req, err := c.CreatePatchRequest(ctx, "/v1/agents/:agent_id/core-memory/blocks/attach/"+blockId)
+	req, err := c.CreatePatchRequest(ctx, "/v1/agents/:agent_id/core-memory/blocks/attach/"+blockId, nil)
 	if err != nil {
 		return fmt.Errorf("%w, %w", ErrRequest, err)
 	}
@@ -62,7 +117,7 @@ func (c *Client) AttachBlock(ctx context.Context, blockId string) error {
 }
 
 func (c *Client) DetachBlock(ctx context.Context, blockId string) error {
-	req, err := c.CreatePatchRequest(ctx, "/v1/agents/:agent_id/core-memory/blocks/detach/"+blockId)
+	req, err := c.CreatePatchRequest(ctx, "/v1/agents/:agent_id/core-memory/blocks/detach/"+blockId, nil)
 	if err != nil {
 		return fmt.Errorf("%w, %w", ErrRequest, err)
 	}
@@ -81,3 +136,25 @@ func (c *Client) DetachBlock(ctx context.Context, blockId string) error {
 
 	return nil
 }
+
+func (c *Client) DeleteBlock(ctx context.Context, blockId string) error {
+	req, err := c.CreateDeleteRequest(ctx, "/v1/blocks/"+blockId)
+	if err != nil {
+		return fmt.Errorf("%w, %w", ErrRequest, err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrResponse, err)
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	// treat an already deleted resource as success so deletes can be retried
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("%w: status %d", ErrBadStatusCode, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -61,9 +61,9 @@ func (c *Client) CreatePutRequest(ctx context.Context, endpoint string, bodyByte
 	return req, nil
 }
 
-func (c *Client) CreatePatchRequest(ctx context.Context, endpoint string) (*http.Request, error) {
+func (c *Client) CreatePatchRequest(ctx context.Context, endpoint string, bodyBytes []byte) (*http.Request, error) {
 	endpoint = c.addAgentName(endpoint)
-	req, err := http.NewRequestWithContext(ctx, "PATCH", c.host+endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.host+endpoint, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -74,13 +74,25 @@ func (c *Client) CreatePatchRequest(ctx context.Context, endpoint string) (*http
 	return req, nil
 }
 
+func (c *Client) CreateDeleteRequest(ctx context.Context, endpoint string) (*http.Request, error) {
+	endpoint = c.addAgentName(endpoint)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.host+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("authorization", "Bearer "+c.apiKey)
+
+	return req, nil
+}
+
 func (c *Client) CreateGetRequest(ctx context.Context, endpoint string) (*http.Request, error) {
 	endpoint = c.addAgentName(endpoint)
 	req, err := http.NewRequestWithContext(ctx, "GET", c.host+endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("authorization", "application/json")
+	req.Header.Set("authorization", "Bearer "+c.apiKey)
 	return req, nil
 }
 
@@ -6,19 +6,70 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/haileyok/penelope/letta/api"
 )
 
-func (c *Client) UpsertIdentity(ctx context.Context, input api.UpsertIdentityInput) error {
+func (c *Client) UpsertIdentity(ctx context.Context, input api.UpsertIdentityInput) (*api.Identity, error) {
 	input.AgentIDs = []string{c.agentName}
 
 	b, err := json.Marshal(input)
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrJsonMarshal, err)
+		return nil, fmt.Errorf("%w: %w", ErrJsonMarshal, err)
 	}
 
 	req, err := c.CreatePutRequest(ctx, "/v1/identities/", b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrResponse, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: status %d", ErrBadStatusCode, resp.StatusCode)
+	}
+
+	var result api.Identity
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJsonUnmarshal, err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) ListIdentities(ctx context.Context, identifierKey string) ([]api.Identity, error) {
+	req, err := c.CreateGetRequest(ctx, "/v1/identities/?identifier_key="+url.QueryEscape(identifierKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrResponse, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: status %d", ErrBadStatusCode, resp.StatusCode)
+	}
+
+	var result []api.Identity
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJsonUnmarshal, err)
+	}
+
+	return result, nil
+}
+
+func (c *Client) DeleteIdentity(ctx context.Context, identityId string) error {
+	req, err := c.CreateDeleteRequest(ctx, "/v1/identities/"+identityId)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrRequest, err)
 	}
@@ -31,7 +82,8 @@ func (c *Client) UpsertIdentity(ctx context.Context, input api.UpsertIdentityInp
 
 	io.Copy(io.Discard, resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
+	// treat an already deleted resource as success so deletes can be retried
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
 		return fmt.Errorf("%w: status %d", ErrBadStatusCode, resp.StatusCode)
 	}
 
@@ -50,7 +50,7 @@ func (c *Client) SendMessage(ctx context.Context, messages []api.Message) (*api.
 }
 
 func (c *Client) ResetMessages(ctx context.Context) error {
-	req, err := c.CreatePatchRequest(ctx, "/v1/agents/:agent_id/reset-messages")
+	req, err := c.CreatePatchRequest(ctx, "/v1/agents/:agent_id/reset-messages", nil)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrRequest, err)
 	}
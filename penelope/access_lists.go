@@ -0,0 +1,209 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	AccessListIgnore = "ignore"
+	AccessListAdmin  = "admin"
+
+	AccessSourceCli     = "cli"
+	AccessSourceApi     = "api"
+	AccessSourceCommand = "command"
+	AccessSourceList    = "list"
+)
+
+func validAccessList(kind string) bool {
+	return kind == AccessListIgnore || kind == AccessListAdmin
+}
+
+func (p *Penelope) isIgnored(did string) bool {
+	p.listsMu.RLock()
+	defer p.listsMu.RUnlock()
+	_, ok := p.ignoreDids[did]
+	return ok
+}
+
+func (p *Penelope) isAdmin(did string) bool {
+	p.listsMu.RLock()
+	defer p.listsMu.RUnlock()
+	_, ok := p.botAdmins[did]
+	return ok
+}
+
+// loadAccessLists refreshes the in memory ignore and admin lists from the database
+func (p *Penelope) loadAccessLists() error {
+	var entries []AccessListEntry
+	if err := p.db.Find(&entries).Error; err != nil {
+		return err
+	}
+
+	ignoreDids := map[string]struct{}{}
+	botAdmins := map[string]struct{}{}
+	for _, e := range entries {
+		switch e.Kind {
+		case AccessListIgnore:
+			ignoreDids[e.Did] = struct{}{}
+		case AccessListAdmin:
+			botAdmins[e.Did] = struct{}{}
+		}
+	}
+
+	p.listsMu.Lock()
+	p.ignoreDids = ignoreDids
+	p.botAdmins = botAdmins
+	p.listsMu.Unlock()
+
+	return nil
+}
+
+// replaceAccessListSource replaces every entry of the given kind from the given source with the supplied dids
+func (p *Penelope) replaceAccessListSource(kind, source string, dids []string) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("kind = ? AND source = ?", kind, source).Delete(&AccessListEntry{}).Error; err != nil {
+			return err
+		}
+		for _, did := range dids {
+			entry := AccessListEntry{
+				Did:    did,
+				Kind:   kind,
+				Source: source,
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return p.loadAccessLists()
+}
+
+func (p *Penelope) listAccessList(kind string) ([]AccessListEntry, error) {
+	var entries []AccessListEntry
+	if err := p.db.Where("kind = ?", kind).Order("created_at").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (p *Penelope) addToAccessList(kind, did, source string) error {
+	entry := AccessListEntry{
+		Did:    did,
+		Kind:   kind,
+		Source: source,
+	}
+	if err := p.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error; err != nil {
+		return err
+	}
+	return p.loadAccessLists()
+}
+
+func (p *Penelope) removeFromAccessList(kind, did string) error {
+	if err := p.db.Where("kind = ? AND did = ?", kind, did).Delete(&AccessListEntry{}).Error; err != nil {
+		return err
+	}
+	return p.loadAccessLists()
+}
+
+// syncAccessList replaces the list sourced entries of the given kind with the members of an app.bsky.graph.list
+func (p *Penelope) syncAccessList(ctx context.Context, kind, listUri string) error {
+	var dids []string
+	var cursor string
+	for {
+		resp, err := bsky.GraphGetList(ctx, p.GetClient(), cursor, 100, listUri)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range resp.Items {
+			if item.Subject == nil {
+				continue
+			}
+			dids = append(dids, item.Subject.Did)
+		}
+
+		if resp.Cursor == nil || *resp.Cursor == "" || len(resp.Items) == 0 {
+			break
+		}
+		cursor = *resp.Cursor
+	}
+
+	if err := p.replaceAccessListSource(kind, AccessSourceList, dids); err != nil {
+		return err
+	}
+
+	p.logger.Info("synced access list", "kind", kind, "list", listUri, "members", len(dids))
+
+	return nil
+}
+
+func (p *Penelope) startAccessListSync(ctx context.Context) {
+	if p.ignoreListUri == "" && p.adminListUri == "" {
+		return
+	}
+
+	syncLists := func() {
+		if p.ignoreListUri != "" {
+			if err := p.syncAccessList(ctx, AccessListIgnore, p.ignoreListUri); err != nil {
+				p.logger.Error("failed to sync ignore list", "error", err)
+			}
+		}
+		if p.adminListUri != "" {
+			if err := p.syncAccessList(ctx, AccessListAdmin, p.adminListUri); err != nil {
+				p.logger.Error("failed to sync admin list", "error", err)
+			}
+		}
+	}
+
+	syncLists()
+
+	ticker := time.NewTicker(p.listSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			syncLists()
+		}
+	}
+}
+
+// resolveActor takes either a handle or a did and returns the did
+func (p *Penelope) resolveActor(ctx context.Context, actor string) (string, error) {
+	actor = strings.TrimPrefix(strings.TrimSpace(actor), "@")
+
+	if strings.HasPrefix(actor, "did:") {
+		did, err := syntax.ParseDID(actor)
+		if err != nil {
+			return "", err
+		}
+		return did.String(), nil
+	}
+
+	handle, err := syntax.ParseHandle(actor)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := atproto.IdentityResolveHandle(ctx, p.GetClient(), handle.Normalize().String())
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve handle: %w", err)
+	}
+
+	return resp.Did, nil
+}
@@ -0,0 +1,419 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/labstack/echo/v4"
+)
+
+func (p *Penelope) handleAdminAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		auth := e.Request().Header.Get("authorization")
+		pts := strings.Split(auth, " ")
+		if len(pts) == 2 && p.adminApiKey != "" {
+			if p.adminApiKey == pts[1] {
+				return next(e)
+			}
+		}
+		return e.JSON(http.StatusForbidden, makeErrorJson("unauthorized"))
+	}
+}
+
+type AccessListEntryInput struct {
+	Actor string `json:"actor"`
+}
+
+type GetAccessListResponse struct {
+	Entries []AccessListEntry `json:"entries"`
+}
+
+func (p *Penelope) handleGetAccessList(e echo.Context) error {
+	kind := e.Param("kind")
+	if !validAccessList(kind) {
+		return e.JSON(400, makeErrorJson("invalid list"))
+	}
+
+	entries, err := p.listAccessList(kind)
+	if err != nil {
+		p.logger.Error("failed to get access list", "kind", kind, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get list"))
+	}
+
+	return e.JSON(200, GetAccessListResponse{
+		Entries: entries,
+	})
+}
+
+func (p *Penelope) handleAddToAccessList(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	kind := e.Param("kind")
+	if !validAccessList(kind) {
+		return e.JSON(400, makeErrorJson("invalid list"))
+	}
+
+	var input AccessListEntryInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	did, err := p.resolveActor(ctx, input.Actor)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid actor"))
+	}
+
+	if err := p.addToAccessList(kind, did, AccessSourceApi); err != nil {
+		p.logger.Error("failed to add to access list", "kind", kind, "did", did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to add to list"))
+	}
+
+	return e.NoContent(200)
+}
+
+func (p *Penelope) handleRemoveFromAccessList(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	kind := e.Param("kind")
+	if !validAccessList(kind) {
+		return e.JSON(400, makeErrorJson("invalid list"))
+	}
+
+	did, err := p.resolveActor(ctx, e.Param("actor"))
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid actor"))
+	}
+
+	if err := p.removeFromAccessList(kind, did); err != nil {
+		p.logger.Error("failed to remove from access list", "kind", kind, "did", did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to remove from list"))
+	}
+
+	return e.NoContent(200)
+}
+
+type AdminStatusResponse struct {
+	QueueDepth int64 `json:"queueDepth"`
+	Paused     bool  `json:"paused"`
+	AdminOnly  bool  `json:"adminOnly"`
+	Ignored    int   `json:"ignored"`
+	Admins     int   `json:"admins"`
+	Blocks     int64 `json:"blocks"`
+}
+
+func (p *Penelope) handleAdminStatus(e echo.Context) error {
+	var blocks int64
+	if err := p.db.Model(&Block{}).Count(&blocks).Error; err != nil {
+		p.logger.Error("failed to count blocks", "error", err)
+		return e.JSON(500, makeErrorJson("failed to get status"))
+	}
+
+	p.listsMu.RLock()
+	ignored, admins := len(p.ignoreDids), len(p.botAdmins)
+	p.listsMu.RUnlock()
+
+	return e.JSON(200, AdminStatusResponse{
+		QueueDepth: p.queueDepth.Load(),
+		Paused:     p.paused.Load(),
+		AdminOnly:  p.adminOnly.Load(),
+		Ignored:    ignored,
+		Admins:     admins,
+		Blocks:     blocks,
+	})
+}
+
+type ListConversationsResponse struct {
+	Conversations []Conversation `json:"conversations"`
+}
+
+func (p *Penelope) handleListConversations(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var q ConversationQuery
+
+	if actor := e.QueryParam("actor"); actor != "" {
+		did, err := p.resolveActor(ctx, actor)
+		if err != nil {
+			return e.JSON(400, makeErrorJson("invalid actor"))
+		}
+		q.Did = did
+	}
+
+	for param, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		v := e.QueryParam(param)
+		if v == "" {
+			continue
+		}
+		parsed, err := dateparse.ParseAny(v)
+		if err != nil {
+			return e.JSON(400, makeErrorJson("invalid "+param))
+		}
+		*t = parsed
+	}
+
+	limit, err := strconv.Atoi(e.QueryParam("limit"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	q.Limit = limit
+
+	convs, err := p.ListConversations(q)
+	if err != nil {
+		p.logger.Error("failed to list conversations", "error", err)
+		return e.JSON(500, makeErrorJson("failed to list conversations"))
+	}
+
+	return e.JSON(200, ListConversationsResponse{
+		Conversations: convs,
+	})
+}
+
+// handlePause stops the bot from replying. The firehose is still consumed while paused, so mentions that arrive in the
+// meantime are discarded rather than answered after resuming.
+func (p *Penelope) handlePause(e echo.Context) error {
+	p.paused.Store(true)
+	p.logger.Info("paused replies from admin api")
+	return e.NoContent(200)
+}
+
+func (p *Penelope) handleResume(e echo.Context) error {
+	p.paused.Store(false)
+	p.logger.Info("resumed replies from admin api")
+	return e.NoContent(200)
+}
+
+type ListBlocksResponse struct {
+	Blocks []Block `json:"blocks"`
+}
+
+func (p *Penelope) handleListBlocks(e echo.Context) error {
+	limit, err := strconv.Atoi(e.QueryParam("limit"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(e.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var blocks []Block
+	if err := p.db.Order("did").Limit(limit).Offset(offset).Find(&blocks).Error; err != nil {
+		p.logger.Error("failed to list blocks", "error", err)
+		return e.JSON(500, makeErrorJson("failed to list blocks"))
+	}
+
+	return e.JSON(200, ListBlocksResponse{
+		Blocks: blocks,
+	})
+}
+
+type GetBlockResponse struct {
+	Did   string `json:"did"`
+	Id    string `json:"id"`
+	Label string `json:"label"`
+	Limit int    `json:"limit"`
+	Value string `json:"value"`
+}
+
+func (p *Penelope) handleGetBlock(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	block, err := p.getBlockForActor(ctx, e.Param("actor"))
+	if err != nil {
+		return e.JSON(404, makeErrorJson("block not found"))
+	}
+
+	lb, err := p.letta.GetBlock(ctx, block.Id)
+	if err != nil {
+		p.logger.Error("failed to get letta block", "block-id", block.Id, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get block from letta"))
+	}
+
+	return e.JSON(200, GetBlockResponse{
+		Did:   block.Did,
+		Id:    block.Id,
+		Label: lb.Label,
+		Limit: lb.Limit,
+		Value: lb.Value,
+	})
+}
+
+func (p *Penelope) handleDetachBlock(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	block, err := p.getBlockForActor(ctx, e.Param("actor"))
+	if err != nil {
+		return e.JSON(404, makeErrorJson("block not found"))
+	}
+
+	// the block might be attached to a conversation that is in progress
+	p.chatMu.Lock()
+	defer p.chatMu.Unlock()
+
+	if err := p.letta.DetachBlock(ctx, block.Id); err != nil {
+		p.logger.Error("failed to detach block", "block-id", block.Id, "error", err)
+		return e.JSON(500, makeErrorJson("failed to detach block"))
+	}
+
+	p.logger.Info("force detached block from admin api", "did", block.Did, "block-id", block.Id)
+
+	return e.NoContent(200)
+}
+
+type GetBlockHistoryResponse struct {
+	Did      string         `json:"did"`
+	Id       string         `json:"id"`
+	Versions []BlockVersion `json:"versions"`
+}
+
+func (p *Penelope) handleGetBlockHistory(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	block, err := p.getBlockForActor(ctx, e.Param("actor"))
+	if err != nil {
+		return e.JSON(404, makeErrorJson("block not found"))
+	}
+
+	limit, err := strconv.Atoi(e.QueryParam("limit"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+
+	versions, err := p.BlockHistory(block.Did, limit)
+	if err != nil {
+		p.logger.Error("failed to get block history", "did", block.Did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get block history"))
+	}
+
+	return e.JSON(200, GetBlockHistoryResponse{
+		Did:      block.Did,
+		Id:       block.Id,
+		Versions: versions,
+	})
+}
+
+func (p *Penelope) handleRestoreBlockVersion(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	block, err := p.getBlockForActor(ctx, e.Param("actor"))
+	if err != nil {
+		return e.JSON(404, makeErrorJson("block not found"))
+	}
+
+	versionId, err := strconv.ParseUint(e.Param("version"), 10, 64)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid version"))
+	}
+
+	version, err := p.restoreBlockVersion(ctx, *block, uint(versionId))
+	if err != nil {
+		p.logger.Error("failed to restore block version", "did", block.Did, "version", versionId, "error", err)
+		return e.JSON(500, makeErrorJson("failed to restore block version"))
+	}
+	if version == nil {
+		return e.JSON(404, makeErrorJson("version not found"))
+	}
+
+	p.logger.Info("restored block version from admin api", "did", block.Did, "block-id", block.Id, "version", version.ID)
+
+	return e.NoContent(200)
+}
+
+type RetractInput struct {
+	Uri string `json:"uri"`
+}
+
+type RetractResponse struct {
+	Deleted []string `json:"deleted"`
+}
+
+func (p *Penelope) handleRetract(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input RetractInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	aturi, err := p.parsePostUri(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid post uri"))
+	}
+
+	uris, err := p.retractPostBatch(ctx, "admin-api", aturi.String())
+	if err != nil {
+		p.logger.Error("failed to retract posts", "uri", aturi.String(), "error", err)
+		return e.JSON(500, makeErrorJson("failed to retract posts: "+err.Error()))
+	}
+
+	p.logger.Info("retracted posts from admin api", "uri", aturi.String(), "deleted", len(uris))
+
+	return e.JSON(200, RetractResponse{
+		Deleted: uris,
+	})
+}
+
+func (p *Penelope) handleResetMessages(e echo.Context) error {
+	p.chatMu.Lock()
+	defer p.chatMu.Unlock()
+
+	if err := p.letta.ResetMessages(e.Request().Context()); err != nil {
+		p.logger.Error("failed to reset messages", "error", err)
+		return e.JSON(500, makeErrorJson("failed to reset messages"))
+	}
+	return e.NoContent(200)
+}
+
+type TestReplyInput struct {
+	Uri string `json:"uri"`
+}
+
+func (p *Penelope) handleTestReply(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input TestReplyInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	resp, err := bsky.FeedGetPosts(ctx, p.GetClient(), []string{input.Uri})
+	if err != nil || len(resp.Posts) == 0 {
+		return e.JSON(404, makeErrorJson("post not found"))
+	}
+
+	pv := resp.Posts[0]
+	rec, ok := pv.Record.Val.(*bsky.FeedPost)
+	if !ok {
+		return e.JSON(400, makeErrorJson("uri is not a post"))
+	}
+
+	p.logger.Info("sending test reply from admin api", "uri", pv.Uri)
+
+	go p.SendMessage(context.WithoutCancel(ctx), rec, pv.Author.Did, pv.Uri, pv.Cid, rec.Text, true)
+
+	return e.NoContent(202)
+}
+
+func (p *Penelope) getBlockForActor(ctx context.Context, actor string) (*Block, error) {
+	did, err := p.resolveActor(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := p.getUserBlock(did)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Id == "" {
+		return nil, fmt.Errorf("no block for %s", did)
+	}
+
+	return &block, nil
+}
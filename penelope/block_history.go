@@ -0,0 +1,147 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/haileyok/penelope/letta/api"
+)
+
+// recordBlockChange stores a new version of a user's block along with a diff from the previous value. If the history
+// does not already contain the previous value (the block was created before history existed or was edited outside of
+// penelope), that value is stored first so that it can still be restored.
+func (p *Penelope) recordBlockChange(b Block, before, after, reason string) error {
+	if before == after {
+		return nil
+	}
+
+	var latest []BlockVersion
+	if err := p.db.Where("block_id = ?", b.Id).Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+		return fmt.Errorf("failed to get latest block version: %w", err)
+	}
+
+	if len(latest) == 0 || latest[0].Value != before {
+		prev := ""
+		if len(latest) > 0 {
+			prev = latest[0].Value
+		}
+
+		if err := p.db.Create(&BlockVersion{
+			Did:     b.Did,
+			BlockId: b.Id,
+			Value:   before,
+			Diff:    lineDiff(prev, before),
+			Reason:  "baseline",
+		}).Error; err != nil {
+			return fmt.Errorf("failed to save baseline block version: %w", err)
+		}
+	}
+
+	return p.db.Create(&BlockVersion{
+		Did:     b.Did,
+		BlockId: b.Id,
+		Value:   after,
+		Diff:    lineDiff(before, after),
+		Reason:  reason,
+	}).Error
+}
+
+// recordConversationBlockChange compares a user's block with the value it had before a conversation and records any
+// changes the agent made
+func (p *Penelope) recordConversationBlockChange(ctx context.Context, b Block, before string) {
+	after, err := p.letta.GetBlock(ctx, b.Id)
+	if err != nil {
+		p.logger.Error("could not snapshot block after conversation", "did", b.Did, "block-id", b.Id, "error", err)
+		return
+	}
+
+	if err := p.recordBlockChange(b, before, after.Value, "conversation"); err != nil {
+		p.logger.Error("failed to record block version", "did", b.Did, "block-id", b.Id, "error", err)
+	}
+}
+
+// BlockHistory returns the stored versions of a user's block, newest first
+func (p *Penelope) BlockHistory(did string, limit int) ([]BlockVersion, error) {
+	var versions []BlockVersion
+	if err := p.db.Where("did = ?", did).Order("id DESC").Limit(limit).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// restoreBlockVersion sets a user's block back to the value it had at the given version
+func (p *Penelope) restoreBlockVersion(ctx context.Context, b Block, versionId uint) (*BlockVersion, error) {
+	p.chatMu.Lock()
+	defer p.chatMu.Unlock()
+
+	var versions []BlockVersion
+	if err := p.db.Where("id = ? AND block_id = ?", versionId, b.Id).Limit(1).Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get block version: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	version := versions[0]
+
+	current, err := p.letta.GetBlock(ctx, b.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block: %w", err)
+	}
+
+	if _, err := p.letta.UpdateBlock(ctx, b.Id, api.UpdateBlockInput{Value: version.Value}); err != nil {
+		return nil, fmt.Errorf("failed to update block: %w", err)
+	}
+
+	if err := p.recordBlockChange(b, current.Value, version.Value, fmt.Sprintf("restore of version %d", version.ID)); err != nil {
+		p.logger.Error("failed to record block version", "did", b.Did, "block-id", b.Id, "error", err)
+	}
+
+	return &version, nil
+}
+
+// lineDiff returns a simple line based diff between two values. Removed lines are prefixed with "-", added lines with
+// "+" and unchanged lines with a space.
+func lineDiff(before, after string) string {
+	var a, b []string
+	if before != "" {
+		a = strings.Split(before, "\n")
+	}
+	if after != "" {
+		b = strings.Split(after, "\n")
+	}
+
+	// longest common subsequence table, lcs[i][j] is the lcs length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString(" " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("+" + b[j] + "\n")
+			j++
+		default:
+			sb.WriteString("-" + a[i] + "\n")
+			i++
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package penelope
+
+import "testing"
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name: "both empty",
+			want: "",
+		},
+		{
+			name:  "new block",
+			after: "a\nb",
+			want:  "+a\n+b\n",
+		},
+		{
+			name:   "cleared block",
+			before: "a\nb",
+			want:   "-a\n-b\n",
+		},
+		{
+			name:   "unchanged",
+			before: "a\nb",
+			after:  "a\nb",
+			want:   " a\n b\n",
+		},
+		{
+			name:   "line added in the middle",
+			before: "a\nc",
+			after:  "a\nb\nc",
+			want:   " a\n+b\n c\n",
+		},
+		{
+			name:   "line removed",
+			before: "a\nb\nc",
+			after:  "a\nc",
+			want:   " a\n-b\n c\n",
+		},
+		{
+			name:   "line changed",
+			before: "a\nb\nc",
+			after:  "a\nx\nc",
+			want:   " a\n+x\n-b\n c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineDiff(tt.before, tt.after); got != tt.want {
+				t.Errorf("lineDiff(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
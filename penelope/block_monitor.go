@@ -0,0 +1,151 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/haileyok/penelope/letta/api"
+)
+
+const compactPrompt = `The following is my core memory block about one of the users I talk to. It is getting close to its size limit of %d characters. Please rewrite it so that it is no more than %d characters long. Keep the identity section from ` + identityStartMarker + ` to ` + identityEndMarker + ` exactly as it is, including both markers. Keep the most important and most recent things I have learned about them, merge duplicate information and drop details that are no longer useful. Respond with only the rewritten memory block.
+
+%s`
+
+// startBlockMonitor periodically measures how full each user's memory block is and compacts the ones that are over the
+// threshold
+func (p *Penelope) startBlockMonitor(ctx context.Context) {
+	if p.blockMonitorInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.blockMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := p.checkBlockCapacity(ctx); err != nil {
+			p.logger.Error("failed to check block capacity", "error", err)
+		}
+	}
+}
+
+func (p *Penelope) checkBlockCapacity(ctx context.Context) error {
+	var blocks []Block
+	if err := p.db.Find(&blocks).Error; err != nil {
+		return err
+	}
+
+	var overThreshold int
+	var maxRatio float64
+	for _, b := range blocks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		lb, err := p.letta.GetBlock(ctx, b.Id)
+		if err != nil {
+			p.logger.Error("failed to get block", "did", b.Did, "block-id", b.Id, "error", err)
+			continue
+		}
+
+		if lb.Limit <= 0 {
+			continue
+		}
+
+		ratio := float64(len(lb.Value)) / float64(lb.Limit)
+		blockFillRatio.Observe(ratio)
+		if ratio > maxRatio {
+			maxRatio = ratio
+		}
+
+		if p.blockCompactionThreshold <= 0 || ratio < p.blockCompactionThreshold {
+			continue
+		}
+
+		overThreshold++
+
+		p.logger.Info("block is over compaction threshold", "did", b.Did, "block-id", b.Id, "ratio", ratio)
+
+		compacted, err := p.compactBlock(ctx, b)
+		if err != nil {
+			blockCompactions.WithLabelValues("error").Inc()
+			p.logger.Error("failed to compact block", "did", b.Did, "block-id", b.Id, "error", err)
+			continue
+		}
+
+		if !compacted {
+			blockCompactions.WithLabelValues("skipped").Inc()
+			continue
+		}
+
+		blockCompactions.WithLabelValues("success").Inc()
+	}
+
+	blocksOverThreshold.Set(float64(overThreshold))
+	blockFillRatioMax.Set(maxRatio)
+
+	return nil
+}
+
+// compactBlock asks the agent to rewrite a user's block into something smaller. The change is recorded in the block's
+// history so that the compaction can be rolled back. The block is fetched again once chatMu is held so that edits made
+// by conversations since the capacity check aren't lost. Returns false if the block no longer needs compacting.
+func (p *Penelope) compactBlock(ctx context.Context, b Block) (bool, error) {
+	p.chatMu.Lock()
+	defer p.chatMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+
+	// the user may have been forgotten while we were waiting on the lock
+	current, err := p.getUserBlock(b.Did)
+	if err != nil {
+		return false, fmt.Errorf("failed to get block from db: %w", err)
+	}
+	if current.Id != b.Id {
+		return false, nil
+	}
+
+	lb, err := p.letta.GetBlock(ctx, b.Id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get block: %w", err)
+	}
+
+	if lb.Limit <= 0 || float64(len(lb.Value))/float64(lb.Limit) < p.blockCompactionThreshold {
+		return false, nil
+	}
+
+	target := lb.Limit / 2
+
+	compacted, err := p.promptAgent(ctx, fmt.Sprintf(compactPrompt, lb.Limit, target, lb.Value))
+	if err != nil {
+		return false, err
+	}
+
+	compacted = strings.TrimSpace(compacted)
+	if compacted == "" {
+		return false, fmt.Errorf("compacted block was empty")
+	}
+	if len(compacted) >= len(lb.Value) {
+		return false, fmt.Errorf("compacted block was not smaller than the original")
+	}
+
+	if _, err := p.letta.UpdateBlock(ctx, b.Id, api.UpdateBlockInput{Value: compacted}); err != nil {
+		return false, fmt.Errorf("failed to update block: %w", err)
+	}
+
+	if err := p.recordBlockChange(b, lb.Value, compacted, "compaction"); err != nil {
+		p.logger.Error("failed to record block version", "did", b.Did, "block-id", b.Id, "error", err)
+	}
+
+	p.logger.Info("compacted block", "did", b.Did, "block-id", b.Id, "before", len(lb.Value), "after", len(compacted))
+
+	return true, nil
+}
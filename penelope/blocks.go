@@ -0,0 +1,58 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/haileyok/penelope/letta/api"
+)
+
+const userBlockLimit = 15000
+
+// getUserBlock returns the memory block mapping for a user. The returned block has an empty id if the user doesn't have
+// a block yet.
+func (p *Penelope) getUserBlock(did string) (Block, error) {
+	var block Block
+	if err := p.db.Raw("SELECT * FROM blocks WHERE did = ?", did).Scan(&block).Error; err != nil {
+		return Block{}, err
+	}
+	return block, nil
+}
+
+// createUserBlock creates a new letta memory block for the user, seeded with their profile and any existing memories,
+// and saves the mapping to the database
+func (p *Penelope) createUserBlock(ctx context.Context, profile *bsky.ActorDefs_ProfileViewDetailed, memories string) (Block, error) {
+	var displayName string
+	if profile.DisplayName != nil {
+		displayName = *profile.DisplayName
+	}
+
+	var description string
+	if profile.Description != nil {
+		description = *profile.Description
+	}
+
+	newBlock, err := p.letta.CreateBlock(ctx, api.CreateBlockInput{
+		Value: fmt.Sprintf(UserBlockValue, profile.Handle, profile.Did, displayName, description, memories),
+		Label: "user-" + profile.Did,
+		Limit: userBlockLimit,
+	})
+	if err != nil {
+		return Block{}, err
+	}
+
+	if newBlock.ID == nil {
+		return Block{}, fmt.Errorf("unexpected nil id for new block")
+	}
+
+	block := Block{
+		Did: profile.Did,
+		Id:  *newBlock.ID,
+	}
+	if err := p.db.Create(&block).Error; err != nil {
+		return Block{}, fmt.Errorf("could not add new block to db: %w", err)
+	}
+
+	return block, nil
+}
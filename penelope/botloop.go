@@ -0,0 +1,153 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/araddon/dateparse"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	signOffText = "I think this is a good place for me to leave this thread. Thanks for chatting!"
+)
+
+var (
+	botProfileWords   = []string{"bot", "automated", "llm"}
+	botProfilePhrases = []string{"🤖", "ai agent", "not a human"}
+)
+
+// checkBotLoop decides whether the bot should keep participating in the thread the given post is part of. Threads
+// that have gotten too deep get a single sign off reply and are ignored afterwards. Authors that reply quicker than a
+// human would and whose profile says they are automated get a single sign off reply and are ignored in that thread
+// afterwards.
+func (p *Penelope) checkBotLoop(ctx context.Context, rc *replyContext, rec *bsky.FeedPost, did, uri, cid string, indexedAt time.Time) (bool, error) {
+	if rec.Reply == nil || rec.Reply.Parent == nil || rec.Reply.Root == nil {
+		return true, nil
+	}
+
+	signedOff, err := p.hasSignedOff(rec.Reply.Root.Uri, did)
+	if err != nil {
+		return false, err
+	}
+	if signedOff {
+		p.logger.Info("skipping post in thread that the bot has already left", "uri", uri, "root", rec.Reply.Root.Uri)
+		return false, nil
+	}
+
+	// the post we are looking at has likely not made it into the appview yet, so it isn't part of the chain
+	chain := rc.chain
+	depth := len(chain) + 1
+	if p.maxThreadDepth > 0 && depth > p.maxThreadDepth {
+		p.logger.Info("thread has exceeded max depth", "uri", uri, "depth", depth)
+		return false, p.signOff(ctx, rec, "", uri, cid, "depth")
+	}
+
+	if p.maxBotExchanges <= 0 {
+		return true, nil
+	}
+
+	// count how many times the bot and this author have gone back and forth, and how many of the author's replies
+	// came in quicker than a human would likely type them
+	var exchanges, fastReplies int
+	lastAuthorTime := indexedAt
+	for i, pv := range chain {
+		if i%2 == 0 {
+			if pv.Author.Did != p.botDid {
+				break
+			}
+			exchanges++
+			botTime, err := dateparse.ParseAny(pv.IndexedAt)
+			if err == nil && p.botReplyCadence > 0 && lastAuthorTime.Sub(botTime) < p.botReplyCadence {
+				fastReplies++
+			}
+		} else {
+			if pv.Author.Did != did {
+				break
+			}
+			t, err := dateparse.ParseAny(pv.IndexedAt)
+			if err != nil {
+				t = time.Time{}
+			}
+			lastAuthorTime = t
+		}
+	}
+
+	// a fast back and forth alone could just be a quick typist, and a bio alone could just be someone who works on
+	// bots, so only leave when both are true
+	if exchanges < p.maxBotExchanges || fastReplies < p.maxBotExchanges || !isAutomatedAccount(rc.profile) {
+		return true, nil
+	}
+
+	p.logger.Info("detected conversation loop with another automated account", "uri", uri, "did", did, "exchanges", exchanges, "fast-replies", fastReplies)
+	return false, p.signOff(ctx, rec, did, uri, cid, "bot-loop")
+}
+
+// isAutomatedAccount checks the user's profile for a bot self-label or for common markers in their name or bio
+func isAutomatedAccount(profile *bsky.ActorDefs_ProfileViewDetailed) bool {
+	for _, l := range profile.Labels {
+		if l.Src == profile.Did && l.Val == "bot" {
+			return true
+		}
+	}
+
+	var text string
+	if profile.DisplayName != nil {
+		text += strings.ToLower(*profile.DisplayName) + " "
+	}
+	if profile.Description != nil {
+		text += strings.ToLower(*profile.Description)
+	}
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, w := range botProfileWords {
+		if slices.Contains(words, w) {
+			return true
+		}
+	}
+
+	for _, ph := range botProfilePhrases {
+		if strings.Contains(text, ph) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasSignedOff returns whether the bot has left the thread entirely or stopped replying to the given author in it
+func (p *Penelope) hasSignedOff(rootUri, did string) (bool, error) {
+	var count int64
+	if err := p.db.Model(&ThreadSignOff{}).Where("root_uri = ? AND (did = ? OR did = '')", rootUri, did).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// signOff replies to the post with a goodbye message and records that the bot has left the thread. If did is empty the
+// bot leaves the thread for everyone, otherwise it only stops replying to that author.
+func (p *Penelope) signOff(ctx context.Context, rec *bsky.FeedPost, did, uri, cid, reason string) error {
+	signOff := ThreadSignOff{
+		RootUri: rec.Reply.Root.Uri,
+		Did:     did,
+		Reason:  reason,
+	}
+	if err := p.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&signOff).Error; err != nil {
+		return fmt.Errorf("failed to save sign off: %w", err)
+	}
+
+	threadSignOffs.WithLabelValues(reason).Inc()
+
+	if _, err := p.replyToPost(ctx, rec, uri, cid, signOffText); err != nil {
+		return fmt.Errorf("failed to send sign off reply: %w", err)
+	}
+
+	return nil
+}
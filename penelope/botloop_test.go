@@ -0,0 +1,63 @@
+package penelope
+
+import (
+	"testing"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+func TestIsAutomatedAccount(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		profile *bsky.ActorDefs_ProfileViewDetailed
+		want    bool
+	}{
+		{
+			name:    "empty profile",
+			profile: &bsky.ActorDefs_ProfileViewDetailed{Did: "did:plc:alice"},
+			want:    false,
+		},
+		{
+			name: "bot self label",
+			profile: &bsky.ActorDefs_ProfileViewDetailed{
+				Did:    "did:plc:alice",
+				Labels: []*atproto.LabelDefs_Label{{Src: "did:plc:alice", Val: "bot"}},
+			},
+			want: true,
+		},
+		{
+			name: "bot label from someone else",
+			profile: &bsky.ActorDefs_ProfileViewDetailed{
+				Did:    "did:plc:alice",
+				Labels: []*atproto.LabelDefs_Label{{Src: "did:plc:mod", Val: "bot"}},
+			},
+			want: false,
+		},
+		{
+			name:    "bot word in bio",
+			profile: &bsky.ActorDefs_ProfileViewDetailed{Did: "did:plc:alice", Description: str("I am a friendly bot.")},
+			want:    true,
+		},
+		{
+			name:    "bot as part of another word",
+			profile: &bsky.ActorDefs_ProfileViewDetailed{Did: "did:plc:alice", Description: str("robotics and bottles")},
+			want:    false,
+		},
+		{
+			name:    "phrase in display name",
+			profile: &bsky.ActorDefs_ProfileViewDetailed{Did: "did:plc:alice", DisplayName: str("Helper 🤖")},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAutomatedAccount(tt.profile); got != tt.want {
+				t.Errorf("isAutomatedAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
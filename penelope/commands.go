@@ -0,0 +1,168 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+const commandPrefix = "!"
+
+type adminCommand struct {
+	Name string
+	Args []string
+}
+
+// parseCommand parses an admin command out of text, for example "!ignore alice.bsky.social"
+func parseCommand(text string) (*adminCommand, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, commandPrefix) {
+		return nil, false
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(text, commandPrefix))
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	return &adminCommand{
+		Name: strings.ToLower(fields[0]),
+		Args: fields[1:],
+	}, true
+}
+
+// postTextWithoutMention returns the text of the post with any mentions of the given did removed
+func postTextWithoutMention(rec *bsky.FeedPost, did string) string {
+	text := []byte(rec.Text)
+	var out []byte
+	var last int64
+	for _, f := range rec.Facets {
+		if f.Index == nil || f.Index.ByteStart < last || f.Index.ByteEnd > int64(len(text)) {
+			continue
+		}
+		for _, ff := range f.Features {
+			if ff.RichtextFacet_Mention == nil || ff.RichtextFacet_Mention.Did != did {
+				continue
+			}
+			out = append(out, text[last:f.Index.ByteStart]...)
+			last = f.Index.ByteEnd
+			break
+		}
+	}
+	out = append(out, text[last:]...)
+	return strings.TrimSpace(string(out))
+}
+
+const commandHelp = `Commands: !pause, !resume, !ignore <handle>, !unignore <handle>, !forget <handle>, !admin-only <on|off>, !retract <post>, !status`
+
+// runAdminCommand executes an admin command and returns the text to reply to the admin with
+func (p *Penelope) runAdminCommand(ctx context.Context, adminDid string, cmd *adminCommand) (string, error) {
+	p.logger.Info("running admin command", "admin", adminDid, "command", cmd.Name, "args", cmd.Args)
+
+	switch cmd.Name {
+	case "pause":
+		p.paused.Store(true)
+		return "Paused. I won't reply to anyone until I'm resumed, and mentions in the meantime won't be answered.", nil
+	case "resume":
+		p.paused.Store(false)
+		return "Resumed. I'm replying to mentions again.", nil
+	case "ignore", "unignore", "forget":
+		if len(cmd.Args) != 1 {
+			return fmt.Sprintf("Usage: !%s <handle>", cmd.Name), nil
+		}
+
+		did, err := p.resolveActor(ctx, cmd.Args[0])
+		if err != nil {
+			return fmt.Sprintf("I couldn't find %s.", cmd.Args[0]), nil
+		}
+
+		switch cmd.Name {
+		case "ignore":
+			if err := p.addToAccessList(AccessListIgnore, did, AccessSourceCommand); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Ignoring %s.", cmd.Args[0]), nil
+		case "unignore":
+			if err := p.removeFromAccessList(AccessListIgnore, did); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("No longer ignoring %s.", cmd.Args[0]), nil
+		default:
+			if err := p.forgetUser(ctx, adminDid, did, "admin command"); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("I've forgotten everything I knew about %s.", cmd.Args[0]), nil
+		}
+	case "admin-only":
+		if len(cmd.Args) != 1 || (cmd.Args[0] != "on" && cmd.Args[0] != "off") {
+			return "Usage: !admin-only <on|off>", nil
+		}
+		p.adminOnly.Store(cmd.Args[0] == "on")
+		return fmt.Sprintf("Admin only mode is %s.", cmd.Args[0]), nil
+	case "retract":
+		if len(cmd.Args) != 1 {
+			return "Usage: !retract <post>", nil
+		}
+
+		aturi, err := p.parsePostUri(ctx, cmd.Args[0])
+		if err != nil {
+			return "That doesn't look like a post.", nil
+		}
+
+		uris, err := p.retractPostBatch(ctx, adminDid, aturi.String())
+		if err != nil {
+			return fmt.Sprintf("I couldn't retract that post: %s", err), nil
+		}
+		return fmt.Sprintf("Deleted %d posts.", len(uris)), nil
+	case "status":
+		return p.statusText()
+	default:
+		return commandHelp, nil
+	}
+}
+
+func (p *Penelope) statusText() (string, error) {
+	var blocks int64
+	if err := p.db.Model(&Block{}).Count(&blocks).Error; err != nil {
+		return "", err
+	}
+
+	p.listsMu.RLock()
+	ignored, admins := len(p.ignoreDids), len(p.botAdmins)
+	p.listsMu.RUnlock()
+
+	replies := "active"
+	if p.paused.Load() {
+		replies = "paused"
+	}
+
+	adminOnly := "off"
+	if p.adminOnly.Load() {
+		adminOnly = "on"
+	}
+
+	return fmt.Sprintf("Replies: %s. Queue: %d. Admin only: %s. Ignored users: %d. Admins: %d. Memory blocks: %d.", replies, p.queueDepth.Load(), adminOnly, ignored, admins, blocks), nil
+}
+
+// handleAdminCommandPost runs an admin command that was sent as a mention and replies with the result. Returns false
+// if the post did not contain a command.
+func (p *Penelope) handleAdminCommandPost(ctx context.Context, rec *bsky.FeedPost, did, uri, cid string) bool {
+	cmd, ok := parseCommand(postTextWithoutMention(rec, p.botDid))
+	if !ok {
+		return false
+	}
+
+	resp, err := p.runAdminCommand(ctx, did, cmd)
+	if err != nil {
+		p.logger.Error("failed to run admin command", "command", cmd.Name, "error", err)
+		resp = "Something went wrong running that command."
+	}
+
+	if _, err := p.replyToPost(ctx, rec, uri, cid, resp); err != nil {
+		p.logger.Error("failed to reply to admin command", "error", err)
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+package penelope
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text string
+		want *adminCommand
+	}{
+		{text: "!pause", want: &adminCommand{Name: "pause", Args: []string{}}},
+		{text: "  !PAUSE  ", want: &adminCommand{Name: "pause", Args: []string{}}},
+		{text: "!ignore alice.bsky.social", want: &adminCommand{Name: "ignore", Args: []string{"alice.bsky.social"}}},
+		{text: "!admin-only   on", want: &adminCommand{Name: "admin-only", Args: []string{"on"}}},
+		{text: "! status", want: &adminCommand{Name: "status", Args: []string{}}},
+		{text: "!"},
+		{text: "pause"},
+		{text: "please !pause"},
+		{text: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := parseCommand(tt.text)
+			if tt.want == nil {
+				if ok {
+					t.Fatalf("parseCommand(%q) = %+v, want no command", tt.text, got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("parseCommand(%q) returned no command", tt.text)
+			}
+			if got.Name != tt.want.Name || !slices.Equal(got.Args, tt.want.Args) {
+				t.Errorf("parseCommand(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostTextWithoutMention(t *testing.T) {
+	mention := func(start, end int64, did string) *bsky.RichtextFacet {
+		return &bsky.RichtextFacet{
+			Index: &bsky.RichtextFacet_ByteSlice{ByteStart: start, ByteEnd: end},
+			Features: []*bsky.RichtextFacet_Features_Elem{
+				{RichtextFacet_Mention: &bsky.RichtextFacet_Mention{Did: did}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		rec  *bsky.FeedPost
+		want string
+	}{
+		{
+			name: "no facets",
+			rec:  &bsky.FeedPost{Text: " !pause "},
+			want: "!pause",
+		},
+		{
+			name: "leading mention",
+			rec:  &bsky.FeedPost{Text: "@bot.test !pause", Facets: []*bsky.RichtextFacet{mention(0, 9, "did:plc:bot")}},
+			want: "!pause",
+		},
+		{
+			name: "mention of someone else is kept",
+			rec:  &bsky.FeedPost{Text: "@bot.test hi @alice.test", Facets: []*bsky.RichtextFacet{mention(0, 9, "did:plc:bot"), mention(13, 24, "did:plc:alice")}},
+			want: "hi @alice.test",
+		},
+		{
+			name: "out of range facet is ignored",
+			rec:  &bsky.FeedPost{Text: "hi", Facets: []*bsky.RichtextFacet{mention(0, 9, "did:plc:bot")}},
+			want: "hi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postTextWithoutMention(tt.rec, "did:plc:bot"); got != tt.want {
+				t.Errorf("postTextWithoutMention(%q) = %q, want %q", tt.rec.Text, got, tt.want)
+			}
+		})
+	}
+}
@@ -58,6 +58,10 @@ func (p *Penelope) startConsumer(ctx context.Context, cancel context.CancelFunc)
 			go p.repoCommit(ctx, evt)
 			return nil
 		},
+		RepoIdentity: func(evt *atproto.SyncSubscribeRepos_Identity) error {
+			go p.repoIdentity(ctx, evt)
+			return nil
+		},
 	}
 
 	d := websocket.DefaultDialer
@@ -139,6 +143,26 @@ func (p *Penelope) repoCommit(ctx context.Context, evt *atproto.SyncSubscribeRep
 				p.logger.Error("error handling create event", "error", err)
 				continue
 			}
+		case repomgr.EvtKindUpdateRecord:
+			if collection.String() != "app.bsky.actor.profile" || rkey.String() != "self" {
+				continue
+			}
+
+			_, rec, err := r.GetRecordBytes(ctx, op.Path)
+			if err != nil {
+				p.logger.Error("failed to get record bytes", "error", err, "path", op.Path)
+				continue
+			}
+
+			if rec == nil {
+				p.logger.Warn("record not found", "path", op.Path)
+				continue
+			}
+
+			if err := p.handleProfileUpdate(ctx, *rec, did.String()); err != nil {
+				p.logger.Error("error handling profile update", "error", err)
+				continue
+			}
 		}
 	}
 }
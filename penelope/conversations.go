@@ -0,0 +1,108 @@
+package penelope
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/haileyok/penelope/letta/api"
+)
+
+// startConversation logs that the bot has started working on a reply to a post. Conversations without a response
+// are ones that failed before a reply was posted.
+func (p *Penelope) startConversation(did, uri, cid, text string) *Conversation {
+	conv := &Conversation{
+		Did:     did,
+		PostUri: uri,
+		PostCid: cid,
+		Text:    text,
+	}
+	if err := p.db.Create(conv).Error; err != nil {
+		p.logger.Error("failed to log conversation", "uri", uri, "error", err)
+	}
+	return conv
+}
+
+// finishConversation records what was sent to and received from letta, and the uris of the posted replies
+func (p *Penelope) finishConversation(conv *Conversation, content string, resp *api.MessageResult, response string, replies []*atproto.RepoStrongRef, err error) {
+	conv.Context = content
+	conv.Response = response
+
+	if resp != nil {
+		conv.PromptTokens = resp.Usage.PromptTokens
+		conv.CompletionTokens = resp.Usage.CompletionTokens
+		conv.TotalTokens = resp.Usage.TotalTokens
+		conv.StepCount = resp.Usage.StepCount
+	}
+
+	var uris []string
+	for _, r := range replies {
+		uris = append(uris, r.Uri)
+	}
+	conv.ReplyUris = strings.Join(uris, "\n")
+
+	if err != nil {
+		conv.Error = err.Error()
+	}
+
+	if err := p.db.Save(conv).Error; err != nil {
+		p.logger.Error("failed to update conversation log", "uri", conv.PostUri, "error", err)
+	}
+}
+
+type ConversationQuery struct {
+	Did   string
+	Since time.Time
+	Until time.Time
+	Limit int
+}
+
+// ListConversations returns logged conversations matching the query, newest first
+func (p *Penelope) ListConversations(q ConversationQuery) ([]Conversation, error) {
+	tx := p.db.Order("created_at DESC")
+	if q.Did != "" {
+		tx = tx.Where("did = ?", q.Did)
+	}
+	if !q.Since.IsZero() {
+		tx = tx.Where("created_at >= ?", q.Since)
+	}
+	if !q.Until.IsZero() {
+		tx = tx.Where("created_at < ?", q.Until)
+	}
+	if q.Limit > 0 {
+		tx = tx.Limit(q.Limit)
+	}
+
+	var convs []Conversation
+	if err := tx.Find(&convs).Error; err != nil {
+		return nil, err
+	}
+
+	return convs, nil
+}
+
+// startConversationRetention periodically deletes conversation logs that are older than the retention period
+func (p *Penelope) startConversationRetention(ctx context.Context) {
+	if p.conversationRetention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		res := p.db.Unscoped().Where("created_at < ?", time.Now().Add(-p.conversationRetention)).Delete(&Conversation{})
+		if res.Error != nil {
+			p.logger.Error("failed to delete old conversations", "error", res.Error)
+		} else if res.RowsAffected > 0 {
+			p.logger.Info("deleted old conversations", "count", res.RowsAffected)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
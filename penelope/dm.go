@@ -0,0 +1,163 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/chat"
+	"github.com/bluesky-social/indigo/xrpc"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	chatProxy          = "did:web:api.bsky.chat#bsky_chat"
+	dmCursorSettingKey = "dm-cursor"
+)
+
+// chatClient returns an xrpc client that proxies requests through the bot's pds to the chat service
+func (p *Penelope) chatClient() *xrpc.Client {
+	p.xmu.RLock()
+	defer p.xmu.RUnlock()
+
+	return &xrpc.Client{
+		Client: p.x.Client,
+		Auth:   p.x.Auth,
+		Host:   p.x.Host,
+		Headers: map[string]string{
+			"atproto-proxy": chatProxy,
+		},
+	}
+}
+
+// convoForUser returns the id of the bot's conversation with the given user, starting one if it doesn't already exist
+func (p *Penelope) convoForUser(ctx context.Context, did string) (string, error) {
+	convo, err := chat.ConvoGetConvoForMembers(ctx, p.chatClient(), []string{did})
+	if err != nil {
+		return "", fmt.Errorf("failed to get convo: %w", err)
+	}
+	return convo.Convo.Id, nil
+}
+
+func (p *Penelope) sendConvoMessage(ctx context.Context, convoId, text string) error {
+	if _, err := chat.ConvoSendMessage(ctx, p.chatClient(), &chat.ConvoSendMessage_Input{
+		ConvoId: convoId,
+		Message: &chat.ConvoDefs_MessageInput{
+			Text: text,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// startDirectMessages polls the chat log for new messages sent to the bot
+func (p *Penelope) startDirectMessages(ctx context.Context) {
+	if !p.enableDms {
+		return
+	}
+
+	cursor, err := p.getSetting(dmCursorSettingKey)
+	if err != nil {
+		p.logger.Error("failed to load dm cursor", "error", err)
+		return
+	}
+
+	// if we've never polled before, skip over any existing history rather than handling old messages
+	skip := cursor == ""
+
+	ticker := time.NewTicker(p.dmPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var failed bool
+		for {
+			resp, err := chat.ConvoGetLog(ctx, p.chatClient(), cursor)
+			if err != nil {
+				p.logger.Error("failed to get chat log", "error", err)
+				failed = true
+				break
+			}
+
+			for _, l := range resp.Logs {
+				if skip || l.ConvoDefs_LogCreateMessage == nil {
+					continue
+				}
+				msg := l.ConvoDefs_LogCreateMessage
+				if msg.Message == nil || msg.Message.ConvoDefs_MessageView == nil {
+					continue
+				}
+				mv := msg.Message.ConvoDefs_MessageView
+				if mv.Sender == nil || mv.Sender.Did == p.botDid {
+					continue
+				}
+				p.handleDirectMessage(ctx, msg.ConvoId, mv.Sender.Did, mv.Text)
+			}
+
+			if resp.Cursor == nil || *resp.Cursor == cursor || len(resp.Logs) == 0 {
+				break
+			}
+
+			cursor = *resp.Cursor
+			if err := p.setSetting(dmCursorSettingKey, cursor); err != nil {
+				p.logger.Error("failed to save dm cursor", "error", err)
+			}
+		}
+
+		// keep skipping until we've caught up to a cursor, otherwise a failed first poll would replay the whole history
+		// (including old admin commands) on the next one
+		if skip && !failed && cursor != "" {
+			skip = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Penelope) handleDirectMessage(ctx context.Context, convoId, did, text string) {
+	if p.handleForgetMeMessage(ctx, convoId, did, text) {
+		return
+	}
+
+	if p.handleExportMessage(ctx, convoId, did, text) {
+		return
+	}
+
+	if !p.isAdmin(did) {
+		return
+	}
+
+	cmd, ok := parseCommand(text)
+	if !ok {
+		return
+	}
+
+	resp, err := p.runAdminCommand(ctx, did, cmd)
+	if err != nil {
+		p.logger.Error("failed to run admin command", "command", cmd.Name, "error", err)
+		resp = "Something went wrong running that command."
+	}
+
+	if err := p.sendConvoMessage(ctx, convoId, resp); err != nil {
+		p.logger.Error("failed to reply to admin command", "error", err)
+	}
+}
+
+func (p *Penelope) getSetting(key string) (string, error) {
+	var setting Setting
+	if err := p.db.Where("key = ?", key).Limit(1).Find(&setting).Error; err != nil {
+		return "", err
+	}
+	return setting.Value, nil
+}
+
+func (p *Penelope) setSetting(key, value string) error {
+	return p.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		UpdateAll: true,
+	}).Create(&Setting{Key: key, Value: value}).Error
+}
@@ -0,0 +1,239 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+var exportPattern = regexp.MustCompile(`(?i)^(please\s+)?(what\s+do\s+you\s+(know|remember)\s+about\s+me|export\s+my\s+(data|memories|memory)|send\s+me\s+(a\s+copy\s+of\s+)?my\s+(data|memories|memory))\b`)
+
+// dms are limited to 1000 graphemes, so exports are sent in chunks a bit smaller than that
+const (
+	maxDirectMessageLength = 900
+	maxExportMessages      = 20
+)
+
+type MemoryExport struct {
+	Did            string                 `json:"did"`
+	BlockId        string                 `json:"blockId,omitempty"`
+	Block          string                 `json:"block,omitempty"`
+	LegacyMemories []string               `json:"legacyMemories,omitempty"`
+	Conversations  []ExportedConversation `json:"conversations,omitempty"`
+}
+
+type ExportedConversation struct {
+	CreatedAt time.Time `json:"createdAt"`
+	PostUri   string    `json:"postUri"`
+	Text      string    `json:"text"`
+	Response  string    `json:"response"`
+	ReplyUris []string  `json:"replyUris,omitempty"`
+}
+
+func isExportRequest(text string) bool {
+	return exportPattern.MatchString(strings.TrimSpace(text))
+}
+
+// ExportUserMemory collects everything the bot remembers about a user
+func (p *Penelope) ExportUserMemory(ctx context.Context, did string) (*MemoryExport, error) {
+	export := &MemoryExport{
+		Did: did,
+	}
+
+	block, err := p.getUserBlock(did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: %w", err)
+	}
+
+	if block.Id != "" {
+		lb, err := p.letta.GetBlock(ctx, block.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get letta block: %w", err)
+		}
+		export.BlockId = block.Id
+		export.Block = lb.Value
+	}
+
+	var memories []UserMemory
+	if err := p.db.Where("did = ?", did).Order("created_at").Find(&memories).Error; err != nil {
+		return nil, fmt.Errorf("failed to get legacy memories: %w", err)
+	}
+	for _, m := range memories {
+		export.LegacyMemories = append(export.LegacyMemories, m.Memory)
+	}
+
+	convs, err := p.ListConversations(ConversationQuery{Did: did})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	for _, c := range convs {
+		ec := ExportedConversation{
+			CreatedAt: c.CreatedAt,
+			PostUri:   c.PostUri,
+			Text:      c.Text,
+			Response:  c.Response,
+		}
+		if c.ReplyUris != "" {
+			ec.ReplyUris = strings.Split(c.ReplyUris, "\n")
+		}
+		export.Conversations = append(export.Conversations, ec)
+	}
+
+	return export, nil
+}
+
+// ExportedDids returns every did that the bot has any memory of
+func (p *Penelope) ExportedDids() ([]string, error) {
+	var dids []string
+	if err := p.db.Raw("SELECT did FROM blocks UNION SELECT did FROM user_memories UNION SELECT did FROM conversations ORDER BY did").Scan(&dids).Error; err != nil {
+		return nil, err
+	}
+	return dids, nil
+}
+
+// Markdown renders the export in a form that is easy for the user to read
+func (e *MemoryExport) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("## What I remember about you\n\n")
+	if e.Block == "" && len(e.LegacyMemories) == 0 {
+		b.WriteString("I don't have any memories about you.\n\n")
+	}
+	if e.Block != "" {
+		b.WriteString(strings.TrimSpace(e.Block) + "\n\n")
+	}
+	for _, m := range e.LegacyMemories {
+		b.WriteString("- " + strings.TrimSpace(m) + "\n")
+	}
+
+	if len(e.Conversations) > 0 {
+		b.WriteString("\n## Our conversations\n\n")
+		for _, c := range e.Conversations {
+			b.WriteString(fmt.Sprintf("**%s** (%s)\n\n", c.CreatedAt.Format(time.RFC3339), c.PostUri))
+			b.WriteString("> " + strings.ReplaceAll(strings.TrimSpace(c.Text), "\n", "\n> ") + "\n\n")
+			if c.Response != "" {
+				b.WriteString(strings.TrimSpace(c.Response) + "\n\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// splitMessageText splits text into chunks no longer than max runes, preferring to break on newlines
+func splitMessageText(text string, max int) []string {
+	var chunks []string
+	var current []rune
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lr := []rune(line)
+		if len(current)+len(lr) > max && len(current) > 0 {
+			chunks = append(chunks, string(current))
+			current = nil
+		}
+		for len(lr) > max {
+			chunks = append(chunks, string(lr[:max]))
+			lr = lr[max:]
+		}
+		current = append(current, lr...)
+	}
+	if strings.TrimSpace(string(current)) != "" {
+		chunks = append(chunks, string(current))
+	}
+	return chunks
+}
+
+// sendExportDirectMessages sends the export to the given convo as a series of messages
+func (p *Penelope) sendExportDirectMessages(ctx context.Context, convoId string, export *MemoryExport) error {
+	chunks := splitMessageText(export.Markdown(), maxDirectMessageLength)
+	if len(chunks) > maxExportMessages {
+		chunks = append(chunks[:maxExportMessages], "That's all I can fit in messages. Ask an admin if you need a complete copy.")
+	}
+
+	for _, c := range chunks {
+		if err := p.sendConvoMessage(ctx, convoId, c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleExportPost sends the author of the post a copy of their memory by dm if they asked for it. Exports are never
+// published, so if a dm can't be sent the reply asks the user to open their dms instead. Returns false if the post was
+// not an export request.
+func (p *Penelope) handleExportPost(ctx context.Context, rec *bsky.FeedPost, did, uri, cid string) bool {
+	if !isExportRequest(postTextWithoutMention(rec, p.botDid)) {
+		return false
+	}
+
+	p.logger.Info("user asked for a memory export", "did", did, "uri", uri)
+
+	text, err := p.deliverExport(ctx, did)
+	if err != nil {
+		p.logger.Error("failed to export memory", "did", did, "error", err)
+		text = "Sorry, something went wrong while I was putting together what I know about you. Please try again later."
+	}
+
+	if _, err := p.replyToPost(ctx, rec, uri, cid, text); err != nil {
+		p.logger.Error("failed to reply to export request", "error", err)
+	}
+
+	return true
+}
+
+const (
+	exportDmsDisabledText = "I can only share what I remember about you privately, but I'm not able to send DMs right now. Please try again later."
+	exportDmsClosedText   = "I can only share what I remember about you privately, but I couldn't send you a DM. Please allow DMs from me (or follow me) and ask again."
+)
+
+// deliverExport sends an export by dm to a user that asked for one in a post and returns the text to reply with
+func (p *Penelope) deliverExport(ctx context.Context, did string) (string, error) {
+	if !p.enableDms {
+		return exportDmsDisabledText, nil
+	}
+
+	convoId, err := p.convoForUser(ctx, did)
+	if err != nil {
+		p.logger.Warn("could not open convo for export", "did", did, "error", err)
+		return exportDmsClosedText, nil
+	}
+
+	export, err := p.ExportUserMemory(ctx, did)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.sendExportDirectMessages(ctx, convoId, export); err != nil {
+		p.logger.Warn("could not dm export", "did", did, "error", err)
+		return exportDmsClosedText, nil
+	}
+
+	return "I've sent you a DM with everything I remember about you.", nil
+}
+
+// handleExportMessage replies to a dm with the sender's memory if they asked for it. Returns false if the message was
+// not an export request.
+func (p *Penelope) handleExportMessage(ctx context.Context, convoId, did, text string) bool {
+	if !isExportRequest(text) {
+		return false
+	}
+
+	p.logger.Info("user asked for a memory export", "did", did, "convo", convoId)
+
+	export, err := p.ExportUserMemory(ctx, did)
+	if err == nil {
+		err = p.sendExportDirectMessages(ctx, convoId, export)
+	}
+	if err != nil {
+		p.logger.Error("failed to export memory", "did", did, "error", err)
+		if err := p.sendConvoMessage(ctx, convoId, "Sorry, something went wrong while I was putting together what I know about you. Please try again later."); err != nil {
+			p.logger.Error("failed to reply to export request", "error", err)
+		}
+	}
+
+	return true
+}
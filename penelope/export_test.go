@@ -0,0 +1,80 @@
+package penelope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsExportRequest(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "what do you know about me", want: true},
+		{text: "What do you remember about me?", want: true},
+		{text: "please export my data", want: true},
+		{text: "export my memories", want: true},
+		{text: "send me a copy of my data", want: true},
+		{text: "  send me my memory  ", want: true},
+		{text: "what do you know about cats", want: false},
+		{text: "I wonder what do you know about me", want: false},
+		{text: "export my data to a csv file", want: true},
+		{text: "forget me", want: false},
+		{text: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := isExportRequest(tt.text); got != tt.want {
+				t.Errorf("isExportRequest(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMessageText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want []string
+	}{
+		{
+			name: "short text",
+			text: "hello",
+			max:  10,
+			want: []string{"hello"},
+		},
+		{
+			name: "breaks on newlines",
+			text: "aaaa\nbbbb\ncccc",
+			max:  10,
+			want: []string{"aaaa\nbbbb\n", "cccc"},
+		},
+		{
+			name: "long lines are split",
+			text: strings.Repeat("a", 25),
+			max:  10,
+			want: []string{strings.Repeat("a", 10), strings.Repeat("a", 10), strings.Repeat("a", 5)},
+		},
+		{
+			name: "empty text",
+			text: "",
+			max:  10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitMessageText(tt.text, tt.max)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitMessageText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("splitMessageText(%q)[%d] = %q, want %q", tt.text, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
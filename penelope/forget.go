@@ -0,0 +1,114 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"gorm.io/gorm"
+)
+
+// forgetMePattern only matches when the request is the whole message, so that things like "forget me not" or "forget me,
+// what's the capital of France" don't wipe someone's memory
+var forgetMePattern = regexp.MustCompile(`(?i)^(please\s+)?(forget\s+(about\s+)?me|forget\s+everything\s+(you\s+know\s+)?about\s+me|delete\s+(all\s+)?my\s+(data|memories))(\s*,?\s*please)?[\s.!]*$`)
+
+const forgetMeText = "Done. I've forgotten everything I remembered about you, including our past conversations."
+
+// isForgetMeRequest returns whether the text is a user asking the bot to forget them
+func isForgetMeRequest(text string) bool {
+	return forgetMePattern.MatchString(strings.TrimSpace(text))
+}
+
+// forgetUser removes everything the bot remembers about a user: the block mapping, any legacy memories, their
+// conversation logs, their letta block and their letta identity. The actor and reason are recorded in the audit log.
+// The letta resources are removed before the database rows, so a failure partway through always leaves the block
+// mapping in place for a retry to find. The letta deletes treat already deleted resources as success, so retrying
+// after a failure finishes the job.
+func (p *Penelope) forgetUser(ctx context.Context, actor, did, reason string) error {
+	p.chatMu.Lock()
+	defer p.chatMu.Unlock()
+
+	block, err := p.getUserBlock(did)
+	if err != nil {
+		return fmt.Errorf("failed to get block: %w", err)
+	}
+
+	detail := reason
+	if block.Id != "" {
+		detail += " (block " + block.Id + ")"
+		if err := p.letta.DeleteBlock(ctx, block.Id); err != nil {
+			return fmt.Errorf("failed to delete letta block %s: %w", block.Id, err)
+		}
+	}
+
+	if err := p.deleteLettaIdentities(ctx, did); err != nil {
+		return err
+	}
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("did = ?", did).Delete(&Block{}).Error; err != nil {
+			return fmt.Errorf("failed to delete block: %w", err)
+		}
+		if err := tx.Unscoped().Where("did = ?", did).Delete(&UserMemory{}).Error; err != nil {
+			return fmt.Errorf("failed to delete legacy memories: %w", err)
+		}
+		if err := tx.Unscoped().Where("did = ?", did).Delete(&Conversation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete conversations: %w", err)
+		}
+		if err := tx.Unscoped().Where("did = ?", did).Delete(&BlockVersion{}).Error; err != nil {
+			return fmt.Errorf("failed to delete block history: %w", err)
+		}
+		return tx.Create(&AuditEntry{
+			Actor:   actor,
+			Action:  "forget",
+			Subject: did,
+			Detail:  detail,
+		}).Error
+	})
+}
+
+// handleForgetMePost forgets the author of the post if they asked to be forgotten and replies publicly. Returns false
+// if the post was not a forget request.
+func (p *Penelope) handleForgetMePost(ctx context.Context, rec *bsky.FeedPost, did, uri, cid string) bool {
+	if !isForgetMeRequest(postTextWithoutMention(rec, p.botDid)) {
+		return false
+	}
+
+	p.logger.Info("user asked to be forgotten", "did", did, "uri", uri)
+
+	text := forgetMeText
+	if err := p.forgetUser(ctx, did, did, "requested in "+uri); err != nil {
+		p.logger.Error("failed to forget user", "did", did, "error", err)
+		text = "Sorry, something went wrong while I was trying to forget you. Please try again later."
+	}
+
+	if _, err := p.replyToPost(ctx, rec, uri, cid, text); err != nil {
+		p.logger.Error("failed to reply to forget request", "error", err)
+	}
+
+	return true
+}
+
+// handleForgetMeMessage forgets the sender of a dm if they asked to be forgotten and replies privately. Returns false
+// if the message was not a forget request.
+func (p *Penelope) handleForgetMeMessage(ctx context.Context, convoId, did, text string) bool {
+	if !isForgetMeRequest(text) {
+		return false
+	}
+
+	p.logger.Info("user asked to be forgotten", "did", did, "convo", convoId)
+
+	resp := forgetMeText
+	if err := p.forgetUser(ctx, did, did, "requested in dm"); err != nil {
+		p.logger.Error("failed to forget user", "did", did, "error", err)
+		resp = "Sorry, something went wrong while I was trying to forget you. Please try again later."
+	}
+
+	if err := p.sendConvoMessage(ctx, convoId, resp); err != nil {
+		p.logger.Error("failed to reply to forget request", "error", err)
+	}
+
+	return true
+}
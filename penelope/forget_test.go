@@ -0,0 +1,38 @@
+package penelope
+
+import "testing"
+
+func TestIsForgetMeRequest(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "forget me", want: true},
+		{text: "Forget me please", want: true},
+		{text: "please forget about me", want: true},
+		{text: "  forget everything you know about me  ", want: true},
+		{text: "forget everything about me", want: true},
+		{text: "delete my data", want: true},
+		{text: "delete all my memories", want: true},
+		{text: "forget me.", want: true},
+		{text: "forget me!!", want: true},
+		{text: "forget me, please", want: true},
+		{text: "forget meow", want: false},
+		{text: "forget me not", want: false},
+		{text: "forget me not, it's a flower", want: false},
+		{text: "forget me, what's the capital of France?", want: false},
+		{text: "delete my data and then tell me a joke", want: false},
+		{text: "don't forget me", want: false},
+		{text: "can you forget about that", want: false},
+		{text: "what do you know about me", want: false},
+		{text: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := isForgetMeRequest(tt.text); got != tt.want {
+				t.Errorf("isForgetMeRequest(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"slices"
 	"time"
 
 	"github.com/araddon/dateparse"
@@ -21,6 +20,11 @@ func (p *Penelope) handleCreate(ctx context.Context, recb []byte, indexedAt, rev
 	switch collection {
 	case "app.bsky.feed.post":
 		return p.handleCreatePost(ctx, rev, recb, uriFromParts(did, collection, rkey), did, collection, rkey, cid, iat)
+	case "app.bsky.actor.profile":
+		if rkey != "self" {
+			return nil
+		}
+		return p.handleProfileUpdate(ctx, recb, did)
 	default:
 		return nil
 	}
@@ -36,22 +40,19 @@ func (p *Penelope) handleCreatePost(ctx context.Context, rev string, recb []byte
 		return err
 	}
 
-	if p.adminOnly {
-		isAdmin := slices.Contains(p.botAdmins, did)
-		if !isAdmin {
-			return nil
-		}
+	mentionsDid := postMentions(&rec, p.botDid)
+
+	if mentionsDid && p.isAdmin(did) && p.handleAdminCommandPost(ctx, &rec, did, uri, cid) {
+		return nil
 	}
 
-	var mentionsDid bool
-	for _, f := range rec.Facets {
-		for _, ff := range f.Features {
-			if ff.RichtextFacet_Mention == nil {
-				continue
-			}
-			if ff.RichtextFacet_Mention.Did == p.botDid {
-				mentionsDid = true
-			}
+	if p.paused.Load() {
+		return nil
+	}
+
+	if p.adminOnly.Load() {
+		if !p.isAdmin(did) {
+			return nil
 		}
 	}
 
@@ -77,13 +78,60 @@ func (p *Penelope) handleCreatePost(ctx context.Context, rev string, recb []byte
 		return nil
 	}
 
-	if slices.Contains(p.ignoreDids, did) {
+	if p.isIgnored(did) {
 		return fmt.Errorf("post from an ignored user")
 	}
 
+	rc, err := p.loadReplyContext(ctx, &rec, did)
+	if err != nil {
+		return fmt.Errorf("failed to load reply context: %w", err)
+	}
+
+	if reason := p.checkReplyAllowed(rc, &rec); reason != "" {
+		repliesSkipped.WithLabelValues(reason).Inc()
+		p.logger.Info("skipping post the bot is not allowed to reply to", "uri", uri, "did", did, "reason", reason)
+		return nil
+	}
+
+	labelAction, labels := p.labelAction(ctx, rc, did, uri)
+	if labelAction == LabelActionIgnore {
+		repliesSkipped.WithLabelValues("label").Inc()
+		p.logger.Info("skipping post because of moderation labels", "uri", uri, "did", did, "labels", labels)
+		return nil
+	}
+
+	allowed, err := p.checkBotLoop(ctx, rc, &rec, did, uri, cid, indexedAt)
+	if err != nil {
+		return fmt.Errorf("failed to check for bot loops: %w", err)
+	}
+	if !allowed {
+		return nil
+	}
+
+	allowed, err = p.checkRateLimits(ctx, &rec, did, uri, cid)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limits: %w", err)
+	}
+	if !allowed {
+		return nil
+	}
+
+	if mentionsDid && p.handleForgetMePost(ctx, &rec, did, uri, cid) {
+		return nil
+	}
+
+	if p.handleExportPost(ctx, &rec, did, uri, cid) {
+		return nil
+	}
+
 	p.logger.Info("got a post to reply to", "uri", uri)
 
-	go p.SendMessage(ctx, &rec, did, uri, cid, rec.Text)
+	useMemory := labelAction != LabelActionReplyWithoutMemory
+	if !useMemory {
+		p.logger.Info("replying without memory because of moderation labels", "uri", uri, "did", did, "labels", labels)
+	}
+
+	go p.SendMessage(ctx, &rec, did, uri, cid, rec.Text, useMemory)
 
 	return nil
 }
@@ -3,3 +3,10 @@ package penelope
 func uriFromParts(did string, collection string, rkey string) string {
 	return "at://" + did + "/" + collection + "/" + rkey
 }
+
+func derefInt(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,221 @@
+package penelope
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/haileyok/penelope/letta/api"
+)
+
+const (
+	identityStartMarker = "<!-- identity -->"
+	identityEndMarker   = "<!-- /identity -->"
+
+	// blocks created before the identity markers were added delimit the section with these lines instead
+	legacyIdentitySectionStart = "Bluesky Handle:"
+	legacyIdentitySectionEnd   = "Where are they from?"
+)
+
+type userIdentity struct {
+	Did         string
+	Handle      string
+	DisplayName string
+	Description string
+}
+
+func identityFromProfile(profile *bsky.ActorDefs_ProfileViewDetailed) userIdentity {
+	ident := userIdentity{
+		Did:    profile.Did,
+		Handle: profile.Handle,
+	}
+	if profile.DisplayName != nil {
+		ident.DisplayName = *profile.DisplayName
+	}
+	if profile.Description != nil {
+		ident.Description = *profile.Description
+	}
+	return ident
+}
+
+// identitySection renders the identity portion of UserBlockValue for the given user, including the markers around it
+func identitySection(ident userIdentity) string {
+	start := strings.Index(UserBlockValue, identityStartMarker)
+	end := strings.Index(UserBlockValue, identityEndMarker) + len(identityEndMarker)
+	return fmt.Sprintf(UserBlockValue[start:end], ident.Handle, ident.Did, ident.DisplayName, ident.Description)
+}
+
+// replaceIdentitySection swaps the identity section of a block value with a freshly rendered one. Blocks from before
+// the identity markers existed have their unmarked section replaced, and if no section can be found at all (for
+// example if it was dropped during compaction) a new one is prepended.
+func replaceIdentitySection(value string, ident userIdentity) string {
+	section := identitySection(ident)
+
+	if start := strings.Index(value, identityStartMarker); start != -1 {
+		if end := strings.Index(value[start:], identityEndMarker); end != -1 {
+			end += start + len(identityEndMarker)
+			return value[:start] + section + value[end:]
+		}
+	}
+
+	if start := strings.Index(value, legacyIdentitySectionStart); start != -1 {
+		if end := strings.Index(value[start:], legacyIdentitySectionEnd); end != -1 {
+			end += start
+			return value[:start] + section + "\n\t" + value[end:]
+		}
+	}
+
+	return section + "\n" + value
+}
+
+// setIdentitySection updates the identity section of a user's block if it has changed. Callers must hold chatMu.
+func (p *Penelope) setIdentitySection(ctx context.Context, b Block, ident userIdentity, reason string) error {
+	lb, err := p.letta.GetBlock(ctx, b.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get block: %w", err)
+	}
+
+	updated := replaceIdentitySection(lb.Value, ident)
+
+	if updated == lb.Value {
+		return nil
+	}
+
+	if _, err := p.letta.UpdateBlock(ctx, b.Id, api.UpdateBlockInput{Value: updated}); err != nil {
+		return fmt.Errorf("failed to update block: %w", err)
+	}
+
+	if err := p.recordBlockChange(b, lb.Value, updated, "identity"); err != nil {
+		p.logger.Error("failed to record block version", "did", b.Did, "block-id", b.Id, "error", err)
+	}
+
+	identityUpdates.WithLabelValues(reason).Inc()
+
+	p.logger.Info("updated identity section of block", "did", b.Did, "block-id", b.Id, "reason", reason)
+
+	return nil
+}
+
+// refreshUserIdentity updates the identity section of a known user's block. The handle and profile record are taken from
+// the firehose event when available, since the appview may not have caught up with the change yet.
+func (p *Penelope) refreshUserIdentity(ctx context.Context, did, reason, handle string, profileRec *bsky.ActorProfile) error {
+	block, err := p.getUserBlock(did)
+	if err != nil {
+		return fmt.Errorf("failed to get block: %w", err)
+	}
+
+	if block.Id == "" {
+		return nil
+	}
+
+	profile, err := bsky.ActorGetProfile(ctx, p.GetClient(), did)
+	if err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	ident := identityFromProfile(profile)
+	if handle != "" && handle != "handle.invalid" {
+		ident.Handle = handle
+	}
+	if profileRec != nil {
+		ident.DisplayName = ""
+		if profileRec.DisplayName != nil {
+			ident.DisplayName = *profileRec.DisplayName
+		}
+		ident.Description = ""
+		if profileRec.Description != nil {
+			ident.Description = *profileRec.Description
+		}
+	}
+
+	if _, err := p.upsertLettaIdentity(ctx, ident, block.Id); err != nil {
+		p.logger.Error("could not upsert letta identity", "did", did, "error", err)
+	}
+
+	p.chatMu.Lock()
+	defer p.chatMu.Unlock()
+
+	return p.setIdentitySection(ctx, block, ident, reason)
+}
+
+// upsertLettaIdentity creates or updates the letta identity for a user, keyed by their did. If blockId is set, the
+// user's memory block is associated with the identity.
+func (p *Penelope) upsertLettaIdentity(ctx context.Context, ident userIdentity, blockId string) (*api.Identity, error) {
+	name := ident.Handle
+	if ident.DisplayName != "" {
+		name = ident.DisplayName
+	}
+
+	input := api.UpsertIdentityInput{
+		IdentifierKey: ident.Did,
+		Name:          name,
+		IdentityType:  "user",
+		Properties: []api.IdentityProperty{
+			{Key: "did", Value: ident.Did, Type: "string"},
+			{Key: "handle", Value: ident.Handle, Type: "string"},
+			{Key: "display-name", Value: ident.DisplayName, Type: "string"},
+			{Key: "description", Value: ident.Description, Type: "string"},
+		},
+	}
+
+	if blockId != "" {
+		input.BlockIDs = []string{blockId}
+	}
+
+	identity, err := p.letta.UpsertIdentity(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity.ID == "" {
+		return nil, fmt.Errorf("unexpected empty id for identity")
+	}
+
+	return identity, nil
+}
+
+// deleteLettaIdentities removes any letta identities keyed by the user's did
+func (p *Penelope) deleteLettaIdentities(ctx context.Context, did string) error {
+	identities, err := p.letta.ListIdentities(ctx, did)
+	if err != nil {
+		return fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	for _, identity := range identities {
+		if identity.IdentifierKey != did {
+			continue
+		}
+		if err := p.letta.DeleteIdentity(ctx, identity.ID); err != nil {
+			return fmt.Errorf("failed to delete identity: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Penelope) repoIdentity(ctx context.Context, evt *atproto.SyncSubscribeRepos_Identity) {
+	var handle string
+	if evt.Handle != nil {
+		handle = *evt.Handle
+	}
+
+	if err := p.refreshUserIdentity(ctx, evt.Did, "identity-event", handle, nil); err != nil {
+		p.logger.Error("failed to refresh user identity", "did", evt.Did, "error", err)
+	}
+}
+
+func (p *Penelope) handleProfileUpdate(ctx context.Context, recb []byte, did string) error {
+	var rec bsky.ActorProfile
+	if err := rec.UnmarshalCBOR(bytes.NewReader(recb)); err != nil {
+		return err
+	}
+
+	if err := p.refreshUserIdentity(ctx, did, "profile-update", "", &rec); err != nil {
+		return fmt.Errorf("failed to refresh user identity: %w", err)
+	}
+
+	return nil
+}
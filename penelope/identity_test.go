@@ -0,0 +1,67 @@
+package penelope
+
+import "testing"
+
+func TestReplaceIdentitySection(t *testing.T) {
+	ident := userIdentity{
+		Did:         "did:plc:alice",
+		Handle:      "alice.test",
+		DisplayName: "Alice",
+		Description: "hello",
+	}
+
+	section := "<!-- identity -->\n\tBluesky Handle: @alice.test\n\tAtproto DID: did:plc:alice\n\tDisplay Name: Alice\n\tProfile Description: hello\n\t<!-- /identity -->"
+	if got := identitySection(ident); got != section {
+		t.Fatalf("identitySection() = %q, want %q", got, section)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "marked section",
+			value: "intro\n\t<!-- identity -->\n\tBluesky Handle: @old.test\n\tDisplay Name: Old\n\t<!-- /identity -->\n\tlikes cats",
+			want:  "intro\n\t" + section + "\n\tlikes cats",
+		},
+		{
+			name:  "marked section rewritten by compaction",
+			value: "<!-- identity -->\nhandle is old.test\n<!-- /identity -->\nlikes cats",
+			want:  section + "\nlikes cats",
+		},
+		{
+			name:  "legacy section",
+			value: "intro\n\tBluesky Handle: @old.test\n\tAtproto DID: did:plc:alice\n\tDisplay Name: Old\n\tProfile Description: old\n\tWhere are they from? Nowhere.",
+			want:  "intro\n\t" + section + "\n\tWhere are they from? Nowhere.",
+		},
+		{
+			name:  "legacy section without end",
+			value: "Bluesky Handle: @old.test\nlikes cats",
+			want:  section + "\nBluesky Handle: @old.test\nlikes cats",
+		},
+		{
+			name:  "start marker without end marker",
+			value: "<!-- identity -->\nlikes cats",
+			want:  section + "\n<!-- identity -->\nlikes cats",
+		},
+		{
+			name:  "no section",
+			value: "likes cats",
+			want:  section + "\nlikes cats",
+		},
+		{
+			name:  "empty block",
+			value: "",
+			want:  section + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replaceIdentitySection(tt.value, ident); got != tt.want {
+				t.Errorf("replaceIdentitySection(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+type LabelAction string
+
+const (
+	LabelActionReply              LabelAction = "reply"
+	LabelActionReplyWithoutMemory LabelAction = "reply-without-memory"
+	LabelActionIgnore             LabelAction = "ignore"
+)
+
+// severity is used to pick the strictest action when a user or post has multiple labels
+func (a LabelAction) severity() int {
+	switch a {
+	case LabelActionIgnore:
+		return 2
+	case LabelActionReplyWithoutMemory:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DefaultLabelPolicy is used for any label value that isn't overridden by the operator
+var DefaultLabelPolicy = map[string]LabelAction{
+	"!hide":         LabelActionIgnore,
+	"!takedown":     LabelActionIgnore,
+	"spam":          LabelActionIgnore,
+	"porn":          LabelActionIgnore,
+	"sexual":        LabelActionIgnore,
+	"nudity":        LabelActionReplyWithoutMemory,
+	"graphic-media": LabelActionIgnore,
+	"gore":          LabelActionIgnore,
+	"impersonation": LabelActionIgnore,
+	"intolerant":    LabelActionIgnore,
+	"threat":        LabelActionIgnore,
+	"!warn":         LabelActionReplyWithoutMemory,
+	"rude":          LabelActionReplyWithoutMemory,
+	"misleading":    LabelActionReplyWithoutMemory,
+}
+
+// ParseLabelPolicy parses entries in the form of label=action on top of the default policy
+func ParseLabelPolicy(entries []string) (map[string]LabelAction, error) {
+	policy := map[string]LabelAction{}
+	for k, v := range DefaultLabelPolicy {
+		policy[k] = v
+	}
+
+	for _, e := range entries {
+		label, action, ok := strings.Cut(e, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label policy entry %q, expected label=action", e)
+		}
+
+		a := LabelAction(strings.TrimSpace(action))
+		switch a {
+		case LabelActionReply, LabelActionReplyWithoutMemory, LabelActionIgnore:
+		default:
+			return nil, fmt.Errorf("invalid action %q for label %q", action, label)
+		}
+
+		policy[strings.TrimSpace(label)] = a
+	}
+
+	return policy, nil
+}
+
+// labelAction looks at the labels on the author and their posts in the thread and returns the strictest action that
+// the label policy gives for them
+func (p *Penelope) labelAction(ctx context.Context, rc *replyContext, did, uri string) (LabelAction, []string) {
+	// copy the profile's labels so appending doesn't write into the profile's backing array
+	labels := slices.Clone(rc.profile.Labels)
+	for _, pv := range rc.chain {
+		if pv.Author.Did == did {
+			labels = append(labels, pv.Labels...)
+		}
+	}
+
+	// the post is probably too new to have been labeled yet, but check in case it has been
+	resp, err := bsky.FeedGetPosts(ctx, p.GetClient(), []string{uri})
+	if err != nil {
+		p.logger.Warn("could not get post labels", "uri", uri, "error", err)
+	} else {
+		for _, pv := range resp.Posts {
+			labels = append(labels, pv.Labels...)
+		}
+	}
+
+	action := LabelActionReply
+	var matched []string
+	for _, l := range activeLabels(labels) {
+		a, ok := p.labelPolicy[l.Val]
+		if !ok {
+			continue
+		}
+		matched = append(matched, l.Val)
+		if a.severity() > action.severity() {
+			action = a
+		}
+	}
+
+	return action, matched
+}
+
+// activeLabels filters out labels that have expired or have been negated
+func activeLabels(labels []*atproto.LabelDefs_Label) []*atproto.LabelDefs_Label {
+	type labelKey struct {
+		src, uri, val string
+	}
+
+	negated := map[labelKey]bool{}
+	for _, l := range labels {
+		if l.Neg != nil && *l.Neg {
+			negated[labelKey{l.Src, l.Uri, l.Val}] = true
+		}
+	}
+
+	var active []*atproto.LabelDefs_Label
+	for _, l := range labels {
+		if (l.Neg != nil && *l.Neg) || negated[labelKey{l.Src, l.Uri, l.Val}] {
+			continue
+		}
+		if l.Exp != nil {
+			exp, err := dateparse.ParseAny(*l.Exp)
+			if err == nil && exp.Before(time.Now()) {
+				continue
+			}
+		}
+		active = append(active, l)
+	}
+
+	return active
+}
@@ -0,0 +1,152 @@
+package penelope
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+)
+
+func TestParseLabelPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]LabelAction
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: map[string]LabelAction{
+				"spam":   LabelActionIgnore,
+				"nudity": LabelActionReplyWithoutMemory,
+			},
+		},
+		{
+			name:    "override a default",
+			entries: []string{"spam=reply"},
+			want: map[string]LabelAction{
+				"spam":   LabelActionReply,
+				"nudity": LabelActionReplyWithoutMemory,
+			},
+		},
+		{
+			name:    "add a label with whitespace",
+			entries: []string{" custom = reply-without-memory "},
+			want: map[string]LabelAction{
+				"custom": LabelActionReplyWithoutMemory,
+				"spam":   LabelActionIgnore,
+			},
+		},
+		{
+			name:    "missing action",
+			entries: []string{"spam"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			entries: []string{"spam=block"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLabelPolicy(tt.entries)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseLabelPolicy(%q) did not return an error", tt.entries)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLabelPolicy(%q) returned an error: %v", tt.entries, err)
+			}
+			for label, action := range tt.want {
+				if got[label] != action {
+					t.Errorf("policy[%q] = %q, want %q", label, got[label], action)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLabelPolicyDoesNotModifyDefaults(t *testing.T) {
+	if _, err := ParseLabelPolicy([]string{"spam=reply"}); err != nil {
+		t.Fatalf("ParseLabelPolicy returned an error: %v", err)
+	}
+	if DefaultLabelPolicy["spam"] != LabelActionIgnore {
+		t.Errorf("DefaultLabelPolicy was modified")
+	}
+}
+
+func TestActiveLabels(t *testing.T) {
+	neg := true
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	label := func(src, val string) *atproto.LabelDefs_Label {
+		return &atproto.LabelDefs_Label{Src: src, Uri: "at://did:plc:alice/app.bsky.feed.post/1", Val: val}
+	}
+
+	tests := []struct {
+		name   string
+		labels []*atproto.LabelDefs_Label
+		want   []string
+	}{
+		{
+			name: "no labels",
+		},
+		{
+			name:   "plain labels",
+			labels: []*atproto.LabelDefs_Label{label("did:plc:mod", "spam"), label("did:plc:mod", "gore")},
+			want:   []string{"spam", "gore"},
+		},
+		{
+			name: "negated label",
+			labels: []*atproto.LabelDefs_Label{
+				label("did:plc:mod", "spam"),
+				func() *atproto.LabelDefs_Label { l := label("did:plc:mod", "spam"); l.Neg = &neg; return l }(),
+				label("did:plc:mod", "gore"),
+			},
+			want: []string{"gore"},
+		},
+		{
+			name: "negation from another labeler",
+			labels: []*atproto.LabelDefs_Label{
+				label("did:plc:mod", "spam"),
+				func() *atproto.LabelDefs_Label { l := label("did:plc:other", "spam"); l.Neg = &neg; return l }(),
+			},
+			want: []string{"spam"},
+		},
+		{
+			name: "negation that only matches when the fields are concatenated",
+			labels: []*atproto.LabelDefs_Label{
+				{Src: "did:plc:mod", Uri: "at://x", Val: "spam"},
+				{Src: "did:plc:mo", Uri: "dat://x", Val: "spam", Neg: &neg},
+			},
+			want: []string{"spam"},
+		},
+		{
+			name: "expiry",
+			labels: []*atproto.LabelDefs_Label{
+				func() *atproto.LabelDefs_Label { l := label("did:plc:mod", "spam"); l.Exp = &past; return l }(),
+				func() *atproto.LabelDefs_Label { l := label("did:plc:mod", "gore"); l.Exp = &future; return l }(),
+			},
+			want: []string{"gore"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := activeLabels(tt.labels)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d active labels, want %d", len(got), len(tt.want))
+			}
+			for i, l := range got {
+				if l.Val != tt.want[i] {
+					t.Errorf("active label %d = %q, want %q", i, l.Val, tt.want[i])
+				}
+			}
+		})
+	}
+}
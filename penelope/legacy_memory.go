@@ -1,5 +1,12 @@
 package penelope
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
 func (p *Penelope) addUserMemory(did, memory string) error {
 	dbMemory := &UserMemory{
 		Rkey:   p.clock.Next().String(),
@@ -14,7 +21,7 @@ func (p *Penelope) addUserMemory(did, memory string) error {
 
 func (p *Penelope) getUserMemory(did string) (string, error) {
 	var dbMemories []UserMemory
-	if err := p.db.Raw("SELECT * FROM user_memories WHERE did = ?", did).Scan(&dbMemories).Error; err != nil {
+	if err := p.db.Raw("SELECT * FROM user_memories WHERE did = ? AND migrated = false AND deleted_at IS NULL", did).Scan(&dbMemories).Error; err != nil {
 		return "", err
 	}
 
@@ -25,3 +32,94 @@ func (p *Penelope) getUserMemory(did string) (string, error) {
 
 	return memories, nil
 }
+
+func (p *Penelope) markMemoriesMigrated(did string) error {
+	return p.db.Model(&UserMemory{}).Where("did = ?", did).Update("migrated", true).Error
+}
+
+type MigrationProgress struct {
+	Did     string
+	Done    int
+	Total   int
+	Skipped bool
+	Err     error
+}
+
+// UnmigratedMemoryUsers returns the number of users that still have legacy memories that haven't been migrated
+func (p *Penelope) UnmigratedMemoryUsers() (int64, error) {
+	var count int64
+	if err := p.db.Model(&UserMemory{}).Where("migrated = ?", false).Distinct("did").Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MigrateLegacyMemories summarizes the legacy memories of every user that hasn't been migrated yet into a letta block.
+// Users that already have a block are only marked as migrated, so the migration can be safely resumed after it is
+// interrupted. At most limit users are migrated if limit is greater than zero.
+func (p *Penelope) MigrateLegacyMemories(ctx context.Context, limit int, progress func(MigrationProgress)) error {
+	var dids []string
+	if err := p.db.Raw("SELECT DISTINCT did FROM user_memories WHERE migrated = false AND deleted_at IS NULL ORDER BY did").Scan(&dids).Error; err != nil {
+		return fmt.Errorf("failed to get dids to migrate: %w", err)
+	}
+
+	if limit > 0 && len(dids) > limit {
+		dids = dids[:limit]
+	}
+
+	for i, did := range dids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		skipped, err := p.migrateUserMemories(ctx, did)
+		progress(MigrationProgress{
+			Did:     did,
+			Done:    i + 1,
+			Total:   len(dids),
+			Skipped: skipped,
+			Err:     err,
+		})
+	}
+
+	return nil
+}
+
+// migrateUserMemories moves a single user's legacy memories into a new block. Returns true if the user already had a
+// block and was only marked as migrated.
+func (p *Penelope) migrateUserMemories(ctx context.Context, did string) (bool, error) {
+	block, err := p.getUserBlock(did)
+	if err != nil {
+		return false, err
+	}
+
+	if block.Id != "" {
+		return true, p.markMemoriesMigrated(did)
+	}
+
+	memories, err := p.getUserMemory(did)
+	if err != nil {
+		return false, err
+	}
+
+	profile, err := bsky.ActorGetProfile(ctx, p.GetClient(), did)
+	if err != nil {
+		return false, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	p.chatMu.Lock()
+	summary, err := p.SummarizeText(ctx, memories)
+	p.chatMu.Unlock()
+	if err != nil {
+		return false, fmt.Errorf("failed to summarize memories: %w", err)
+	}
+	if summary == "" {
+		return false, fmt.Errorf("summary of memories was empty")
+	}
+
+	if _, err := p.createUserBlock(ctx, profile, summary); err != nil {
+		return false, fmt.Errorf("failed to create block: %w", err)
+	}
+
+	return false, p.markMemoriesMigrated(did)
+}
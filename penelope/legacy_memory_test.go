@@ -0,0 +1,83 @@
+package penelope
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLegacyMemoriesBeforeMigratedColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "penelope.db")
+
+	// create the table the way it looked before the migrated column was added
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE user_memories (id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, updated_at datetime, deleted_at datetime, rkey text, did text, memory text)").Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	for _, m := range []struct{ did, memory string }{
+		{"did:plc:alice", "likes cats"},
+		{"did:plc:alice", "lives in tokyo"},
+		{"did:plc:bob", "likes dogs"},
+	} {
+		if err := db.Exec("INSERT INTO user_memories (did, memory) VALUES (?, ?)", m.did, m.memory).Error; err != nil {
+			t.Fatalf("failed to insert memory: %v", err)
+		}
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get db: %v", err)
+	}
+	sqlDB.Close()
+
+	db, err = openDB(path)
+	if err != nil {
+		t.Fatalf("failed to migrate db: %v", err)
+	}
+	clock := syntax.NewTIDClock(0)
+	p := &Penelope{db: db, clock: &clock}
+
+	count, err := p.UnmigratedMemoryUsers()
+	if err != nil {
+		t.Fatalf("failed to count unmigrated users: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("got %d unmigrated users, want 2", count)
+	}
+
+	tests := []struct {
+		did  string
+		want string
+	}{
+		{did: "did:plc:alice", want: "likes cats\nlives in tokyo\n"},
+		{did: "did:plc:bob", want: "likes dogs\n"},
+		{did: "did:plc:carol", want: ""},
+	}
+	for _, tt := range tests {
+		got, err := p.getUserMemory(tt.did)
+		if err != nil {
+			t.Fatalf("failed to get memories for %s: %v", tt.did, err)
+		}
+		if got != tt.want {
+			t.Errorf("getUserMemory(%s) = %q, want %q", tt.did, got, tt.want)
+		}
+	}
+
+	if err := p.markMemoriesMigrated("did:plc:alice"); err != nil {
+		t.Fatalf("failed to mark memories migrated: %v", err)
+	}
+	if got, _ := p.getUserMemory("did:plc:alice"); got != "" {
+		t.Errorf("getUserMemory returned %q after migrating", got)
+	}
+	if err := p.addUserMemory("did:plc:carol", "likes birds"); err != nil {
+		t.Fatalf("failed to add memory: %v", err)
+	}
+	if count, _ := p.UnmigratedMemoryUsers(); count != 2 {
+		t.Errorf("got %d unmigrated users after migrating one and adding one, want 2", count)
+	}
+}
@@ -0,0 +1,53 @@
+package penelope
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bluesky-social/indigo/lex/util"
+)
+
+const (
+	LongformPlatformWhitewind    = "whitewind"
+	LongformPlatformStandardSite = "standard-site"
+)
+
+// LongformPublisher writes long form posts to the bot's repo using a specific atproto lexicon
+type LongformPublisher interface {
+	Publish(ctx context.Context, draft LongformDraft) (*LongformEntry, error)
+}
+
+// LongformDraft is the content of a long form post that is about to be written. Visibility is only used by platforms
+// that support it.
+type LongformDraft struct {
+	Title      string
+	Subtitle   string
+	Content    string
+	Visibility string
+	Images     []LongformImageInput
+}
+
+// LongformEntry is a long form post that was written to the bot's repo
+type LongformEntry struct {
+	Uri         string
+	Cid         string
+	Url         string
+	Title       string
+	Description string
+	Thumb       *util.LexBlob
+}
+
+// cleanWhitewindContent strips the markers the agent wraps long form content in
+func cleanWhitewindContent(content string) string {
+	content = strings.ReplaceAll(content, "<BEGIN_WHITEWIND_CONTENT>", "")
+	content = strings.ReplaceAll(content, "<END_WHITEWIND_CONTENT>", "")
+	return strings.TrimSpace(content)
+}
+
+type whitewindPublisher struct {
+	p *Penelope
+}
+
+func (wp *whitewindPublisher) Publish(ctx context.Context, draft LongformDraft) (*LongformEntry, error) {
+	return wp.p.createWhitewindPost(ctx, draft)
+}
@@ -0,0 +1,100 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+const maxAnnouncementDescription = 200
+
+// longformExcerpt returns the start of a long form post's content to use as a description
+func longformExcerpt(content string) string {
+	text := markdownImagePattern.ReplaceAllString(content, "")
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= maxAnnouncementDescription {
+		return text
+	}
+
+	cut := strings.LastIndex(text[:maxAnnouncementDescription], " ")
+	if cut <= 0 {
+		cut = maxAnnouncementDescription
+	}
+
+	return strings.ToValidUTF8(text[:cut], "") + "…"
+}
+
+// announceLongformPost publishes a bluesky post with a link card for a long form post. If replyTo is set, the post is
+// made as a reply to that post. Returns the strong ref of the announcement post.
+func (p *Penelope) announceLongformPost(ctx context.Context, entry *LongformEntry, text, replyTo string) (*atproto.RepoStrongRef, error) {
+	if text = strings.TrimSpace(text); text == "" {
+		text = entry.Title
+	}
+	if text == "" {
+		text = "I wrote something new"
+	}
+
+	external := &bsky.EmbedExternal_External{
+		Uri:         entry.Url,
+		Title:       entry.Title,
+		Description: entry.Description,
+	}
+	// link card thumbnails are limited to 1mb, so larger images are left out
+	if entry.Thumb != nil && entry.Thumb.Size <= 1_000_000 {
+		external.Thumb = entry.Thumb
+	}
+
+	embed := &bsky.FeedPost_Embed{
+		EmbedExternal: &bsky.EmbedExternal{
+			External: external,
+		},
+	}
+
+	var root, parent *atproto.RepoStrongRef
+	if replyTo != "" {
+		var err error
+		root, parent, err = p.replyRefs(ctx, replyTo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refs, err := p.createPostChainWithEmbed(ctx, root, parent, text, embed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create announcement: %w", err)
+	}
+
+	return refs[0], nil
+}
+
+// replyRefs resolves the root and parent strong refs needed to reply to the post at uri
+func (p *Penelope) replyRefs(ctx context.Context, uri string) (*atproto.RepoStrongRef, *atproto.RepoStrongRef, error) {
+	resp, err := bsky.FeedGetPosts(ctx, p.GetClient(), []string{uri})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	if len(resp.Posts) == 0 {
+		return nil, nil, fmt.Errorf("post not found")
+	}
+
+	post := resp.Posts[0]
+	if post.Viewer != nil && post.Viewer.ReplyDisabled != nil && *post.Viewer.ReplyDisabled {
+		return nil, nil, fmt.Errorf("replies to this post are not allowed")
+	}
+
+	parent := &atproto.RepoStrongRef{
+		Uri: post.Uri,
+		Cid: post.Cid,
+	}
+
+	root := parent
+	if rec, ok := post.Record.Val.(*bsky.FeedPost); ok && rec.Reply != nil && rec.Reply.Root != nil {
+		root = rec.Reply.Root
+	}
+
+	return root, parent, nil
+}
@@ -0,0 +1,247 @@
+package penelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/lex/util"
+)
+
+const (
+	maxLongformImageSize      = 5 << 20
+	longformImageTimeout      = 30 * time.Second
+	maxLongformImageRedirects = 5
+)
+
+var errNonPublicAddress = errors.New("refusing to connect to a non-public address")
+
+// nonPublicPrefixes are ranges that aren't covered by the netip helpers used in isPublicAddr but still shouldn't be
+// reachable from urls the agent hands us
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// longformImageClient is used to fetch images from urls supplied by the agent. Since those urls can point anywhere,
+// the dialer refuses to connect to anything other than public addresses once the host has been resolved, which also
+// covers redirects and dns names that resolve to internal addresses.
+var longformImageClient = &http.Client{
+	Timeout: longformImageTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				ap, err := netip.ParseAddrPort(address)
+				if err != nil {
+					return err
+				}
+				if !isPublicAddr(ap.Addr()) {
+					return fmt.Errorf("%w: %s", errNonPublicAddress, ap.Addr())
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxLongformImageRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxLongformImageRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("invalid redirect url")
+		}
+		return nil
+	},
+}
+
+// isPublicAddr returns whether the address is a globally routable unicast address
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	// IsGlobalUnicast already excludes loopback, link-local, multicast and unspecified addresses
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+var markdownImagePattern = regexp.MustCompile(`!\[([^\]]*)\]\(\s*([^)\s]+)(\s+"[^"]*")?\s*\)`)
+
+// LongformImageInput is an image to include in a long form post. Either Url or Data (base64, optionally as a data url)
+// must be set. Markdown image links in the content that point at the Url or Name are rewritten to the uploaded blob.
+type LongformImageInput struct {
+	Url  string `json:"url"`
+	Data string `json:"data"`
+	Name string `json:"name"`
+	Alt  string `json:"alt"`
+}
+
+// uploadedImage is an image that has been uploaded to the bot's pds
+type uploadedImage struct {
+	Blob *util.LexBlob
+	Name string
+}
+
+// blobUrl returns the url the bot's pds serves a blob from
+func (p *Penelope) blobUrl(cid string) string {
+	return fmt.Sprintf("%s/xrpc/com.atproto.sync.getBlob?did=%s&cid=%s", p.GetClient().Host, url.QueryEscape(p.botDid), url.QueryEscape(cid))
+}
+
+func (p *Penelope) readLongformImage(ctx context.Context, img LongformImageInput) ([]byte, error) {
+	if img.Data != "" {
+		data := img.Data
+		if strings.HasPrefix(data, "data:") {
+			_, after, found := strings.Cut(data, ",")
+			if !found {
+				return nil, fmt.Errorf("invalid data url")
+			}
+			data = after
+		}
+
+		b, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image data: %w", err)
+		}
+		if len(b) > maxLongformImageSize {
+			return nil, fmt.Errorf("image is larger than %d bytes", maxLongformImageSize)
+		}
+		return b, nil
+	}
+
+	u, err := url.Parse(img.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid image url")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, longformImageTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := longformImageClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch image: status %d", resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxLongformImageSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", maxLongformImageSize)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxLongformImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(b) > maxLongformImageSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", maxLongformImageSize)
+	}
+
+	return b, nil
+}
+
+func (p *Penelope) uploadBlob(ctx context.Context, b []byte, mimeType string) (*util.LexBlob, error) {
+	var out atproto.RepoUploadBlob_Output
+	if err := p.GetClient().LexDo(ctx, util.Procedure, mimeType, "com.atproto.repo.uploadBlob", nil, bytes.NewReader(b), &out); err != nil {
+		return nil, err
+	}
+	if out.Blob == nil {
+		return nil, fmt.Errorf("pds returned no blob")
+	}
+	return out.Blob, nil
+}
+
+// uploadLongformImages uploads the images to the bot's pds and rewrites the markdown image links in content to point
+// at the uploaded blobs. Images that aren't referenced in the content are appended to the end of it.
+func (p *Penelope) uploadLongformImages(ctx context.Context, content string, images []LongformImageInput) (string, []uploadedImage, error) {
+	if len(images) == 0 {
+		return content, nil, nil
+	}
+
+	var uploaded []uploadedImage
+	refs := map[string]string{}
+	var urls []string
+	for i, img := range images {
+		if img.Url == "" && img.Data == "" {
+			return "", nil, fmt.Errorf("image %d has no url or data", i)
+		}
+
+		b, err := p.readLongformImage(ctx, img)
+		if err != nil {
+			return "", nil, fmt.Errorf("image %d: %w", i, err)
+		}
+
+		mimeType := http.DetectContentType(b)
+		if !strings.HasPrefix(mimeType, "image/") {
+			return "", nil, fmt.Errorf("image %d is not an image (%s)", i, mimeType)
+		}
+
+		blob, err := p.uploadBlob(ctx, b, mimeType)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to upload image %d: %w", i, err)
+		}
+
+		uploaded = append(uploaded, uploadedImage{Blob: blob, Name: img.Name})
+
+		blobUrl := p.blobUrl(blob.Ref.String())
+		urls = append(urls, blobUrl)
+		if img.Url != "" {
+			refs[img.Url] = blobUrl
+		}
+		if img.Name != "" {
+			refs[img.Name] = blobUrl
+		}
+	}
+
+	used := map[string]bool{}
+	content = markdownImagePattern.ReplaceAllStringFunc(content, func(m string) string {
+		sm := markdownImagePattern.FindStringSubmatch(m)
+		blobUrl, ok := refs[sm[2]]
+		if !ok {
+			return m
+		}
+		used[blobUrl] = true
+		return "![" + sm[1] + "](" + blobUrl + sm[3] + ")"
+	})
+
+	for i, blobUrl := range urls {
+		if used[blobUrl] {
+			continue
+		}
+		content += "\n\n![" + images[i].Alt + "](" + blobUrl + ")"
+	}
+
+	return content, uploaded, nil
+}
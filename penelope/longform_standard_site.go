@@ -0,0 +1,102 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/lex/util"
+)
+
+const standardSiteDocumentCollection = "site.standard.document"
+
+func init() {
+	util.RegisterType(standardSiteDocumentCollection, &StandardSiteDocument{})
+}
+
+type StandardSiteDocument struct {
+	LexiconTypeID string        `json:"$type,const=site.standard.document" cborgen:"$type,const=site.standard.document"`
+	Site          string        `json:"site" cborgen:"site"`
+	Path          *string       `json:"path,omitempty" cborgen:"path,omitempty"`
+	Title         string        `json:"title" cborgen:"title"`
+	Description   *string       `json:"description,omitempty" cborgen:"description,omitempty"`
+	CoverImage    *util.LexBlob `json:"coverImage,omitempty" cborgen:"coverImage,omitempty"`
+	TextContent   *string       `json:"textContent,omitempty" cborgen:"textContent,omitempty"`
+	PublishedAt   string        `json:"publishedAt" cborgen:"publishedAt"`
+}
+
+// standardSitePublisher writes site.standard.document records. site is the at-uri of the site.standard.publication
+// record (or the site's url) and baseUrl is where the publication's documents are served from.
+type standardSitePublisher struct {
+	p       *Penelope
+	site    string
+	baseUrl string
+}
+
+func (sp *standardSitePublisher) Publish(ctx context.Context, draft LongformDraft) (*LongformEntry, error) {
+	p := sp.p
+
+	content := cleanWhitewindContent(draft.Content)
+	if content == "" {
+		return nil, fmt.Errorf("content is empty")
+	}
+
+	if draft.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	// documents can only reference a single cover image, and any other uploaded blobs would be garbage collected by
+	// the pds
+	if len(draft.Images) > 1 {
+		return nil, fmt.Errorf("standard.site documents support at most one image")
+	}
+
+	content, images, err := p.uploadLongformImages(ctx, content, draft.Images)
+	if err != nil {
+		return nil, err
+	}
+
+	rkey := p.clock.Next().String()
+	path := "/" + rkey
+
+	rec := StandardSiteDocument{
+		LexiconTypeID: standardSiteDocumentCollection,
+		Site:          sp.site,
+		Path:          &path,
+		Title:         draft.Title,
+		TextContent:   &content,
+		PublishedAt:   syntax.DatetimeNow().String(),
+	}
+	if draft.Subtitle != "" {
+		rec.Description = &draft.Subtitle
+	}
+	if len(images) > 0 {
+		rec.CoverImage = images[0].Blob
+	}
+
+	out, err := atproto.RepoCreateRecord(ctx, p.GetClient(), &atproto.RepoCreateRecord_Input{
+		Collection: standardSiteDocumentCollection,
+		Repo:       p.botDid,
+		Rkey:       &rkey,
+		Record:     &util.LexiconTypeDecoder{Val: &rec},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create record: %w", err)
+	}
+
+	description := draft.Subtitle
+	if description == "" {
+		description = longformExcerpt(content)
+	}
+
+	return &LongformEntry{
+		Uri:         out.Uri,
+		Cid:         out.Cid,
+		Url:         strings.TrimSuffix(sp.baseUrl, "/") + path,
+		Title:       rec.Title,
+		Description: description,
+		Thumb:       rec.CoverImage,
+	}, nil
+}
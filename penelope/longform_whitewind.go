@@ -0,0 +1,126 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/lex/util"
+)
+
+const whitewindCollection = "com.whtwnd.blog.entry"
+
+func init() {
+	util.RegisterType(whitewindCollection, &WhitewindRecord{})
+}
+
+type WhitewindRecord struct {
+	LexiconTypeID string                   `json:"$type,const=com.whtwnd.blog.entry" cborgen:"$type,const=com.whtwnd.blog.entry"`
+	Content       string                   `json:"content" cborgen:"content"`
+	CreatedAt     string                   `json:"createdAt" cborgen:"createdAt"`
+	Theme         string                   `json:"theme" cborgen:"theme"`
+	Title         string                   `json:"title" cborgen:"title"`
+	Visibility    string                   `json:"visibility" cborgen:"visibility"`
+	Subtitle      string                   `json:"subtitle" cborgen:"subtitle"`
+	Blobs         []*WhitewindBlobMetadata `json:"blobs,omitempty" cborgen:"blobs,omitempty"`
+}
+
+type WhitewindBlobMetadata struct {
+	Blobref *util.LexBlob `json:"blobref" cborgen:"blobref"`
+	Name    *string       `json:"name,omitempty" cborgen:"name,omitempty"`
+}
+
+const (
+	WhitewindVisibilityPublic = "public"
+	WhitewindVisibilityUrl    = "url"
+	WhitewindVisibilityAuthor = "author"
+)
+
+func validWhitewindVisibility(visibility string) bool {
+	switch visibility {
+	case WhitewindVisibilityPublic, WhitewindVisibilityUrl, WhitewindVisibilityAuthor:
+		return true
+	default:
+		return false
+	}
+}
+
+func whitewindUrl(did, rkey string) string {
+	return "https://whtwnd.com/" + did + "/" + rkey
+}
+
+func whitewindBlobs(images []uploadedImage) []*WhitewindBlobMetadata {
+	var blobs []*WhitewindBlobMetadata
+	for _, img := range images {
+		meta := &WhitewindBlobMetadata{Blobref: img.Blob}
+		if img.Name != "" {
+			meta.Name = &img.Name
+		}
+		blobs = append(blobs, meta)
+	}
+	return blobs
+}
+
+func whitewindEntry(uri, cid, url string, rec *WhitewindRecord) *LongformEntry {
+	entry := &LongformEntry{
+		Uri:         uri,
+		Cid:         cid,
+		Url:         url,
+		Title:       rec.Title,
+		Description: rec.Subtitle,
+	}
+	if entry.Description == "" {
+		entry.Description = longformExcerpt(rec.Content)
+	}
+	if len(rec.Blobs) > 0 {
+		entry.Thumb = rec.Blobs[0].Blobref
+	}
+	return entry
+}
+
+func (p *Penelope) createWhitewindPost(ctx context.Context, draft LongformDraft) (*LongformEntry, error) {
+	content := cleanWhitewindContent(draft.Content)
+	if content == "" {
+		return nil, fmt.Errorf("content is empty")
+	}
+
+	if !validWhitewindVisibility(draft.Visibility) {
+		return nil, fmt.Errorf("invalid visibility %q", draft.Visibility)
+	}
+
+	content, images, err := p.uploadLongformImages(ctx, content, draft.Images)
+	if err != nil {
+		return nil, err
+	}
+
+	rkey := p.clock.Next().String()
+	rec := WhitewindRecord{
+		LexiconTypeID: whitewindCollection,
+		CreatedAt:     time.Now().Format(time.RFC3339Nano),
+		Content:       content,
+		Title:         draft.Title,
+		Subtitle:      draft.Subtitle,
+		Visibility:    draft.Visibility,
+		Theme:         "github-light",
+		Blobs:         whitewindBlobs(images),
+	}
+
+	out, err := atproto.RepoCreateRecord(ctx, p.GetClient(), &atproto.RepoCreateRecord_Input{
+		Collection: whitewindCollection,
+		Repo:       p.botDid,
+		Rkey:       &rkey,
+		Record:     &util.LexiconTypeDecoder{Val: &rec},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create record: %w", err)
+	}
+
+	aturi, err := syntax.ParseATURI(out.Uri)
+	if err != nil {
+		return nil, fmt.Errorf("pds returned invalid uri: %w", err)
+	}
+
+	return whitewindEntry(out.Uri, out.Cid, whitewindUrl(p.botDid, aturi.RecordKey().String()), &rec), nil
+}
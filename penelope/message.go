@@ -2,32 +2,35 @@ package penelope
 
 import (
 	"context"
-	"errors"
-	"time"
-
-	"bytes"
 	"fmt"
-	"strings"
+	"time"
 
-	"github.com/bluesky-social/indigo/api/atproto"
 	"github.com/bluesky-social/indigo/api/bsky"
-	"github.com/bluesky-social/indigo/atproto/syntax"
-	"github.com/bluesky-social/indigo/lex/util"
 	"github.com/haileyok/penelope/letta/api"
 	gocid "github.com/ipfs/go-cid"
-	"gorm.io/gorm"
-	"mvdan.cc/xurls/v2"
 )
 
 var cidbuilder = gocid.V1Builder{Codec: 0x71, MhType: 0x12, MhLength: 0}
 
-func (p *Penelope) SendMessage(ctx context.Context, rec *bsky.FeedPost, did, uri, cid, c string) {
+// SendMessage sends the post to the agent and replies with its response. If useMemory is false, the user's memory
+// block will not be created or attached for this message.
+func (p *Penelope) SendMessage(ctx context.Context, rec *bsky.FeedPost, did, uri, cid, c string, useMemory bool) {
+	p.queueDepth.Add(1)
 	p.chatMu.Lock()
+	p.queueDepth.Add(-1)
+
+	conv := p.startConversation(did, uri, cid, rec.Text)
 
 	var block Block
+	var blockBefore *api.Block
 	defer func(ctx context.Context) {
-		if err := p.letta.DetachBlock(ctx, block.Id); err != nil {
-			p.logger.Error("could not detatch block from agent", "error", err)
+		if blockBefore != nil {
+			p.recordConversationBlockChange(ctx, block, blockBefore.Value)
+		}
+		if block.Id != "" {
+			if err := p.letta.DetachBlock(ctx, block.Id); err != nil {
+				p.logger.Error("could not detatch block from agent", "error", err)
+			}
 		}
 		if err := p.letta.ResetMessages(ctx); err != nil {
 			p.logger.Error("could not reset message", "error", err)
@@ -44,77 +47,48 @@ func (p *Penelope) SendMessage(ctx context.Context, rec *bsky.FeedPost, did, uri
 		return
 	}
 
-	identityProperties := []api.IdentityProperty{
-		{Key: "did", Value: did, Type: "string"}, {Key: "handle", Value: profile.Handle},
-	}
-
-	var displayName string
-	if profile.DisplayName != nil {
-		identityProperties = append(identityProperties, api.IdentityProperty{
-			Key:   "display-name",
-			Value: profile.DisplayName,
-			Type:  "string",
-		})
-		displayName = *profile.DisplayName
-	}
-
-	var description string
-	if profile.Description != nil {
-		identityProperties = append(identityProperties, api.IdentityProperty{
-			Key:   "description",
-			Value: profile.Description,
-			Type:  "string",
-		})
-		description = *profile.Description
-	}
-
-	if err := p.db.Raw("SELECT * FROM blocks WHERE did = ?", did).Scan(&block).Error; err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
+	if useMemory {
+		block, err = p.getUserBlock(did)
+		if err != nil {
 			p.logger.Error("error getting block from db", "error", err)
 			return
 		}
-	}
 
-	if block.Id == "" {
-		var currentMemories string
-		memories, err := p.getUserMemory(did)
-		if err == nil && memories != "" {
-			p.logger.Info("found existing memories for user, migrating", "did", did)
-			summary, err := p.SummarizeText(ctx, memories)
+		if block.Id == "" {
+			var currentMemories string
+			if p.lazyMemoryMigration {
+				memories, err := p.getUserMemory(did)
+				if err == nil && memories != "" {
+					p.logger.Info("found existing memories for user, migrating", "did", did)
+					summary, err := p.SummarizeText(ctx, memories)
+					if err != nil {
+						p.logger.Error("could not summarize memories", "error", err)
+					}
+					p.logger.Info("summarized memories", "summary", summary)
+					currentMemories = summary
+				}
+			}
+
+			block, err = p.createUserBlock(ctx, profile, currentMemories)
 			if err != nil {
-				p.logger.Error("could not summarize memories", "error", err)
+				p.logger.Error("could not create block", "error", err)
+				return
 			}
-			p.logger.Info("summarized memories", "summary", summary)
-			currentMemories = summary
-		}
 
-		newBlock, err := p.letta.CreateBlock(ctx, api.CreateBlockInput{
-			Value: fmt.Sprintf(UserBlockValue, profile.Handle, did, displayName, description, currentMemories),
-			Label: "user-" + did,
-			Limit: 15000,
-		})
-		if err != nil {
-			p.logger.Error("could not create block", "error", err)
-			return
-		}
+			if currentMemories != "" {
+				if err := p.markMemoriesMigrated(did); err != nil {
+					p.logger.Error("could not mark memories as migrated", "error", err)
+				}
+			}
 
-		if newBlock.ID == nil {
-			p.logger.Error("unexpected nil id for new block", "block", block)
-			return
-		}
+			p.logger.Info("created memory block for user", "did", did, "block-id", block.Id)
+		} else {
+			p.logger.Info("found memory block id for user", "did", did, "block-id", block.Id)
 
-		block = Block{
-			Did: did,
-			Id:  *newBlock.ID,
-		}
-		if err := p.db.Create(&block).Error; err != nil {
-			p.logger.Error("could not add new block to db", "error", err)
-			return
+			if err := p.setIdentitySection(ctx, block, identityFromProfile(profile), "conversation"); err != nil {
+				p.logger.Error("could not update identity section of block", "error", err)
+			}
 		}
-
-		p.logger.Info("created memory block for user", "did", did, "block-id", block.Id)
-	} else {
-		p.logger.Info("found memory block id for user", "did", did, "block-id", block.Id)
 	}
 
 	threadSummary, err := p.LoadThread(ctx, rec.Reply)
@@ -123,9 +97,30 @@ func (p *Penelope) SendMessage(ctx context.Context, rec *bsky.FeedPost, did, uri
 		return
 	}
 
-	if err := p.letta.AttachBlock(ctx, block.Id); err != nil {
-		p.logger.Error("could not attach block to agent", "error", err)
-		return
+	if block.Id != "" {
+		lb, err := p.letta.GetBlock(ctx, block.Id)
+		if err != nil {
+			p.logger.Error("could not snapshot block", "error", err)
+		} else {
+			blockBefore = lb
+		}
+
+		if err := p.letta.AttachBlock(ctx, block.Id); err != nil {
+			p.logger.Error("could not attach block to agent", "error", err)
+			return
+		}
+	}
+
+	// fall back to the did as the sender if the identity can't be upserted so the conversation can still go through.
+	// users that shouldn't be remembered don't get an identity at all, since it would store their profile in letta.
+	senderId := did
+	if useMemory {
+		identity, err := p.upsertLettaIdentity(ctx, identityFromProfile(profile), block.Id)
+		if err != nil {
+			p.logger.Error("could not upsert letta identity", "error", err)
+		} else {
+			senderId = identity.ID
+		}
 	}
 
 	var content string
@@ -138,31 +133,18 @@ func (p *Penelope) SendMessage(ctx context.Context, rec *bsky.FeedPost, did, uri
 		{
 			Role:     "user",
 			Content:  content,
-			SenderID: &did,
+			SenderID: &senderId,
 		},
 	})
 	if err != nil {
 		p.logger.Error("error sending message", "error", err)
+		p.finishConversation(conv, content, nil, "", nil, err)
 		return
 	}
 
-	parents := []*atproto.RepoStrongRef{{
-		Uri: uri,
-		Cid: cid,
-	}}
-
-	var root *atproto.RepoStrongRef
-	if rec.Reply != nil {
-		root = rec.Reply.Root
-	} else {
-		root = &atproto.RepoStrongRef{
-			Uri: uri,
-			Cid: cid,
-		}
-	}
-
 	if len(resp.Messages) == 0 {
 		p.logger.Error("message response contained more than one message", "messages-length", len(resp.Messages))
+		p.finishConversation(conv, content, resp, "", nil, fmt.Errorf("empty response from letta"))
 		return
 	}
 
@@ -178,88 +160,27 @@ func (p *Penelope) SendMessage(ctx context.Context, rec *bsky.FeedPost, did, uri
 		response = arguments.Message
 	}
 
-	postTexts := []string{}
-	var currentText string
-	words := strings.Split(response, " ")
-	for i, w := range words {
-		currentText += w + " "
-		if len(words)-1 == i || len(currentText) >= 250 {
-			postTexts = append(postTexts, currentText)
-			currentText = ""
-		}
-	}
-
-	var writes []*atproto.RepoApplyWrites_Input_Writes_Elem
-	for _, pt := range postTexts {
-		pt = strings.TrimSpace(pt)
-		if pt == "" {
-			continue
-		}
-
-		rkey := p.clock.Next().String()
-		post := bsky.FeedPost{
-			Text:      pt,
-			CreatedAt: syntax.DatetimeNow().String(),
-			Reply: &bsky.FeedPost_ReplyRef{
-				Parent: parents[len(parents)-1],
-				Root:   root,
-			},
-		}
-
-		strict := xurls.Strict()
-		urls := strict.FindAllString(pt, -1)
-		if len(urls) != 0 {
-			post.Embed = &bsky.FeedPost_Embed{
-				EmbedExternal: &bsky.EmbedExternal{
-					External: &bsky.EmbedExternal_External{
-						Uri: urls[0],
-					},
-				},
-			}
-		}
-
-		writes = append(writes, &atproto.RepoApplyWrites_Input_Writes_Elem{
-			RepoApplyWrites_Create: &atproto.RepoApplyWrites_Create{
-				Collection: "app.bsky.feed.post",
-				Rkey:       &rkey,
-				Value:      &util.LexiconTypeDecoder{Val: &post},
-			},
-		})
-
-		cborBytes := new(bytes.Buffer)
-		err = post.MarshalCBOR(cborBytes)
-		cidFromJson, err := cidbuilder.Sum(cborBytes.Bytes())
-		if err != nil {
-			p.logger.Error("error getting cid")
-		}
-
-		parents = append(parents, &atproto.RepoStrongRef{
-			Uri: fmt.Sprintf("at://%s/app.bsky.feed.post/%s", p.botDid, rkey),
-			Cid: cidFromJson.String(),
-		})
-	}
-
-	input := &atproto.RepoApplyWrites_Input{
-		Repo:   p.botDid,
-		Writes: writes,
-	}
-
-	_, err = atproto.RepoApplyWrites(ctx, p.x, input)
+	replies, err := p.replyToPost(ctx, rec, uri, cid, response)
 	if err != nil {
 		p.logger.Error("error creating post", "error", err)
+		p.finishConversation(conv, content, resp, response, nil, err)
 		return
 	}
 
+	p.finishConversation(conv, content, resp, response, replies, nil)
+
 	p.logger.Info("replying to post with message", "msg", response)
 }
 
 const (
 	UserBlockValue = `This is my section of core memory devoted to information about the user.
 	I currently know the following about them:
+	<!-- identity -->
 	Bluesky Handle: @%s
 	Atproto DID: %s
 	Display Name: %s
 	Profile Description: %s
+	<!-- /identity -->
 	Where are they from? What do they do? Who are they? What do they post about?
 	I should update this memory over time as I interact with the human and learn more about them.
 
@@ -268,6 +189,12 @@ const (
 )
 
 func (p *Penelope) SummarizeText(ctx context.Context, text string) (string, error) {
+	return p.promptAgent(ctx, "Please take the following text and form a 1-3 paragraph summary of it. You shouldn't feel like you need to make it too short, but stay under 3 paragraphs if possible.\n\n"+text)
+}
+
+// promptAgent sends a one off prompt to the agent and returns its response, resetting the message buffer afterwards.
+// Callers that might run alongside conversations should hold chatMu.
+func (p *Penelope) promptAgent(ctx context.Context, prompt string) (string, error) {
 	defer func() {
 		p.letta.ResetMessages(ctx)
 	}()
@@ -275,16 +202,15 @@ func (p *Penelope) SummarizeText(ctx context.Context, text string) (string, erro
 	resp, err := p.letta.SendMessage(ctx, []api.Message{
 		{
 			Role:    "user",
-			Content: "Please take the following text and form a 1-3 paragraph summary of it. You shouldn't feel like you need to make it too short, but stay under 3 paragraphs if possible.\n\n" + text,
+			Content: prompt,
 		},
 	})
 	if err != nil {
-		p.logger.Error("error sending message", "error", err)
-		return "", nil
+		return "", fmt.Errorf("error sending message: %w", err)
 	}
 
 	if len(resp.Messages) == 0 {
-		return "", fmt.Errorf("error summarizing text. response was empty")
+		return "", fmt.Errorf("error prompting agent. response was empty")
 	}
 
 	var response string
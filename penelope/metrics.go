@@ -0,0 +1,54 @@
+package penelope
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	throttledEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "penelope_throttled_events",
+		Help: "Number of replies that were dropped because of a rate limit",
+	}, []string{"scope"})
+
+	threadSignOffs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "penelope_thread_sign_offs",
+		Help: "Number of threads the bot has signed off from",
+	}, []string{"reason"})
+
+	blockFillRatio = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "penelope_block_fill_ratio",
+		Help:    "How full user memory blocks are relative to their limit",
+		Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+	})
+
+	blockFillRatioMax = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "penelope_block_fill_ratio_max",
+		Help: "Fill ratio of the fullest user memory block in the last check",
+	})
+
+	blocksOverThreshold = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "penelope_blocks_over_threshold",
+		Help: "Number of user memory blocks that were over the compaction threshold in the last check",
+	})
+
+	blockCompactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "penelope_block_compactions",
+		Help: "Number of user memory block compactions",
+	}, []string{"result"})
+
+	identityUpdates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "penelope_identity_updates",
+		Help: "Number of times the identity section of a user memory block was updated",
+	}, []string{"reason"})
+
+	socialActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "penelope_social_actions",
+		Help: "Number of likes, reposts, quotes and follows made by the agent",
+	}, []string{"action"})
+
+	repliesSkipped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "penelope_replies_skipped",
+		Help: "Number of posts the bot did not reply to because it is not allowed to",
+	}, []string{"reason"})
+)
@@ -1,6 +1,10 @@
 package penelope
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Block struct {
 	Did string `gorm:"uniqueIndex"`
@@ -9,7 +13,82 @@ type Block struct {
 
 type UserMemory struct {
 	gorm.Model
-	Rkey   string `gorm:"index"`
-	Did    string `gorm:"index"`
-	Memory string
+	Rkey     string `gorm:"index"`
+	Did      string `gorm:"index"`
+	Memory   string
+	Migrated bool `gorm:"not null;default:false;index"`
+}
+
+type RateLimitBucket struct {
+	Key        string `gorm:"uniqueIndex"`
+	Tokens     float64
+	LastRefill time.Time
+	Notified   bool
+}
+
+// ThreadSignOff records that the bot has left a thread. Did is the author the bot stopped replying to, or empty if the
+// bot left the thread for everyone.
+type ThreadSignOff struct {
+	gorm.Model
+	RootUri string `gorm:"uniqueIndex:idx_thread_sign_off"`
+	Did     string `gorm:"uniqueIndex:idx_thread_sign_off"`
+	Reason  string
+}
+
+type AccessListEntry struct {
+	Did       string `gorm:"uniqueIndex:idx_access_list_entry"`
+	Kind      string `gorm:"uniqueIndex:idx_access_list_entry"`
+	Source    string `gorm:"index"`
+	CreatedAt time.Time
+}
+
+type Setting struct {
+	Key   string `gorm:"uniqueIndex"`
+	Value string
+}
+
+type Conversation struct {
+	gorm.Model
+	Did              string `gorm:"index"`
+	PostUri          string `gorm:"index"`
+	PostCid          string
+	Text             string
+	Context          string
+	Response         string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	StepCount        int
+	ReplyUris        string
+	Error            string
+}
+
+type AuditEntry struct {
+	gorm.Model
+	Actor   string `gorm:"index"`
+	Action  string
+	Subject string `gorm:"index"`
+	Detail  string
+}
+
+type BlockVersion struct {
+	gorm.Model
+	Did     string `gorm:"index"`
+	BlockId string `gorm:"index"`
+	Value   string
+	Diff    string
+	Reason  string
+}
+
+type SentPost struct {
+	gorm.Model
+	Uri      string `gorm:"uniqueIndex"`
+	Cid      string
+	BatchId  string `gorm:"index"`
+	Position int
+}
+
+type SocialAction struct {
+	gorm.Model
+	Action string `gorm:"index"`
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -25,6 +26,8 @@ import (
 	"gorm.io/gorm"
 )
 
+const publicAppviewHost = "https://public.api.bsky.app"
+
 type Penelope struct {
 	h           *http.Client
 	x           *xrpc.Client
@@ -40,13 +43,45 @@ type Penelope struct {
 	relayHost   string
 	metricsAddr string
 	botDid      string
-	botAdmins   []string
+	botAdmins   map[string]struct{}
 	processMu   sync.Mutex
 	chatMu      sync.Mutex
-	ignoreDids  []string
+	ignoreDids  map[string]struct{}
 	clock       *syntax.TIDClock
-	adminOnly   bool
+	adminOnly   atomic.Bool
 	apiKey      string
+
+	rateLimitMu     sync.Mutex
+	userRateLimit   RateLimitConfig
+	globalRateLimit RateLimitConfig
+
+	maxThreadDepth  int
+	maxBotExchanges int
+	botReplyCadence time.Duration
+
+	labelPolicy map[string]LabelAction
+
+	listsMu          sync.RWMutex
+	adminApiKey      string
+	ignoreListUri    string
+	adminListUri     string
+	listSyncInterval time.Duration
+
+	// paused stops the bot from replying to posts. Posts seen while paused are dropped, not queued.
+	paused         atomic.Bool
+	enableDms      bool
+	dmPollInterval time.Duration
+
+	queueDepth            atomic.Int64
+	conversationRetention time.Duration
+	lazyMemoryMigration   bool
+
+	blockMonitorInterval     time.Duration
+	blockCompactionThreshold float64
+
+	longformPublishers map[string]LongformPublisher
+
+	socialActionLimits map[string]int
 }
 
 type Args struct {
@@ -70,6 +105,38 @@ type Args struct {
 	AdminOnly          bool
 	ApiKey             string
 	Addr               string
+
+	UserRateLimitBurst    int
+	UserRateLimitRefill   time.Duration
+	GlobalRateLimitBurst  int
+	GlobalRateLimitRefill time.Duration
+
+	MaxThreadDepth  int
+	MaxBotExchanges int
+	BotReplyCadence time.Duration
+
+	LabelPolicy []string
+	Labelers    []string
+
+	AdminApiKey      string
+	IgnoreListUri    string
+	AdminListUri     string
+	ListSyncInterval time.Duration
+
+	EnableDms      bool
+	DmPollInterval time.Duration
+
+	DbPath                string
+	ConversationRetention time.Duration
+	LazyMemoryMigration   bool
+
+	BlockMonitorInterval     time.Duration
+	BlockCompactionThreshold float64
+
+	StandardSitePublication string
+	StandardSiteUrl         string
+
+	SocialActionLimits []string
 }
 
 func New(ctx context.Context, args *Args) (*Penelope, error) {
@@ -83,14 +150,10 @@ func New(ctx context.Context, args *Args) (*Penelope, error) {
 		Timeout: 1200 * time.Second,
 	}
 
-	db, err := gorm.Open(sqlite.Open("penelope.db"), &gorm.Config{})
+	db, err := openDB(args.DbPath)
 	if err != nil {
 		return nil, err
 	}
-	db.AutoMigrate(
-		&UserMemory{},
-		&Block{},
-	)
 
 	conn, err := clickhouse.Open(&clickhouse.Options{
 		Addr: []string{args.ClickhouseAddr},
@@ -104,10 +167,26 @@ func New(ctx context.Context, args *Args) (*Penelope, error) {
 		return nil, err
 	}
 
+	labelPolicy, err := ParseLabelPolicy(args.LabelPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	socialActionLimits, err := ParseSocialActionLimits(args.SocialActionLimits)
+	if err != nil {
+		return nil, err
+	}
+
 	x := &xrpc.Client{
 		Host: args.BotPdsHost,
 	}
 
+	if len(args.Labelers) > 0 {
+		x.Headers = map[string]string{
+			"atproto-accept-labelers": strings.Join(args.Labelers, ", "),
+		}
+	}
+
 	args.Logger.Info("authenticating with pds...")
 
 	resp, err := atproto.ServerCreateSession(ctx, x, &atproto.ServerCreateSession_Input{
@@ -160,7 +239,7 @@ func New(ctx context.Context, args *Args) (*Penelope, error) {
 		Addr:    args.Addr,
 	}
 
-	return &Penelope{
+	p := &Penelope{
 		h:           h,
 		x:           x,
 		letta:       letta,
@@ -173,14 +252,132 @@ func New(ctx context.Context, args *Args) (*Penelope, error) {
 		relayHost:   args.RelayHost,
 		metricsAddr: args.MetricsAddr,
 		botDid:      args.BotDid,
-		botAdmins:   args.BotAdmins,
-		ignoreDids:  args.IgnoreDids,
 		clock:       &clock,
-		adminOnly:   args.AdminOnly,
 		apiKey:      args.ApiKey,
+		userRateLimit: RateLimitConfig{
+			Burst:  args.UserRateLimitBurst,
+			Refill: args.UserRateLimitRefill,
+		},
+		globalRateLimit: RateLimitConfig{
+			Burst:  args.GlobalRateLimitBurst,
+			Refill: args.GlobalRateLimitRefill,
+		},
+		maxThreadDepth:   args.MaxThreadDepth,
+		maxBotExchanges:  args.MaxBotExchanges,
+		botReplyCadence:  args.BotReplyCadence,
+		labelPolicy:      labelPolicy,
+		adminApiKey:      args.AdminApiKey,
+		ignoreListUri:    args.IgnoreListUri,
+		adminListUri:     args.AdminListUri,
+		listSyncInterval: args.ListSyncInterval,
+		enableDms:        args.EnableDms,
+		dmPollInterval:   args.DmPollInterval,
+
+		conversationRetention: args.ConversationRetention,
+		lazyMemoryMigration:   args.LazyMemoryMigration,
+
+		blockMonitorInterval:     args.BlockMonitorInterval,
+		blockCompactionThreshold: args.BlockCompactionThreshold,
+
+		socialActionLimits: socialActionLimits,
+	}
+
+	p.adminOnly.Store(args.AdminOnly)
+
+	p.longformPublishers = map[string]LongformPublisher{
+		LongformPlatformWhitewind: &whitewindPublisher{p: p},
+	}
+	if args.StandardSiteUrl != "" {
+		site := args.StandardSitePublication
+		if site == "" {
+			site = args.StandardSiteUrl
+		}
+		p.longformPublishers[LongformPlatformStandardSite] = &standardSitePublisher{
+			p:       p,
+			site:    site,
+			baseUrl: args.StandardSiteUrl,
+		}
+	}
+
+	if err := p.replaceAccessListSource(AccessListIgnore, AccessSourceCli, args.IgnoreDids); err != nil {
+		return nil, fmt.Errorf("failed to save ignored dids: %w", err)
+	}
+
+	if err := p.replaceAccessListSource(AccessListAdmin, AccessSourceCli, args.BotAdmins); err != nil {
+		return nil, fmt.Errorf("failed to save bot admins: %w", err)
+	}
+
+	p.addRoutes()
+
+	return p, nil
+}
+
+// NewOffline creates a Penelope that is only able to talk to the database, letta and the public appview. It is
+// used for maintenance commands that shouldn't authenticate with the pds or connect to the relay.
+func NewOffline(args *Args) (*Penelope, error) {
+	if args.Logger == nil {
+		args.Logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelInfo,
+		}))
+	}
+
+	db, err := openDB(args.DbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	letta, _ := letta.NewClient(&letta.ClientArgs{
+		Host:      args.LettaHost,
+		ApiKey:    args.LettaApiKey,
+		AgentName: args.LettaAgentName,
+	})
+
+	clock := syntax.NewTIDClock(0)
+
+	return &Penelope{
+		h: &http.Client{
+			Timeout: 1200 * time.Second,
+		},
+		x: &xrpc.Client{
+			Host: publicAppviewHost,
+		},
+		letta:  letta,
+		db:     db,
+		logger: args.Logger,
+		botDid: args.BotDid,
+		clock:  &clock,
 	}, nil
 }
 
+func openDB(path string) (*gorm.DB, error) {
+	if path == "" {
+		path = "penelope.db"
+	}
+
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.AutoMigrate(
+		&UserMemory{},
+		&Block{},
+		&RateLimitBucket{},
+		&ThreadSignOff{},
+		&AccessListEntry{},
+		&Setting{},
+		&Conversation{},
+		&AuditEntry{},
+		&BlockVersion{},
+		&SentPost{},
+		&SocialAction{},
+	); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
 func (p *Penelope) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -208,6 +405,14 @@ func (p *Penelope) Run(ctx context.Context) error {
 		}
 	}(ctx, cancel)
 
+	go p.startAccessListSync(ctx)
+
+	go p.startDirectMessages(ctx)
+
+	go p.startConversationRetention(ctx)
+
+	go p.startBlockMonitor(ctx)
+
 	go func() {
 		ticker := time.NewTicker(1 * time.Hour)
 		for range ticker.C {
@@ -259,8 +464,43 @@ func (p *Penelope) addRoutes() {
 	g := p.echo.Group("/tools")
 	g.Use(p.handleAuthMiddleware)
 	g.POST("/recent-posts", p.handleGetRecentPosts)
+	g.POST("/search-posts", p.handleSearchPosts)
+	g.POST("/search-users", p.handleSearchUsers)
+	g.POST("/get-profile", p.handleGetProfile)
+	g.POST("/get-relationship", p.handleGetRelationship)
 	g.POST("/create-top-level-post", p.handleCreateTopLevelPost)
-	g.POST("/create-whitewind-post", p.handleCreateWhitewindPost)
+	g.POST("/reply", p.handleReply)
+	g.POST("/like", p.handleLike)
+	g.POST("/unlike", p.handleUnlike)
+	g.POST("/repost", p.handleRepost)
+	g.POST("/quote-post", p.handleQuotePost)
+	g.POST("/follow", p.handleFollow)
+	g.POST("/unfollow", p.handleUnfollow)
+	g.POST("/delete-post", p.handleDeletePost)
+	g.POST("/create-whitewind-post", p.handleCreateLongformPost)
+	g.POST("/create-longform-post", p.handleCreateLongformPost)
+	g.POST("/list-whitewind-posts", p.handleListWhitewindPosts)
+	g.POST("/get-whitewind-post", p.handleGetWhitewindPost)
+	g.POST("/update-whitewind-post", p.handleUpdateWhitewindPost)
+	g.POST("/delete-whitewind-post", p.handleDeleteWhitewindPost)
+
+	ag := p.echo.Group("/admin")
+	ag.Use(p.handleAdminAuthMiddleware)
+	ag.GET("/lists/:kind", p.handleGetAccessList)
+	ag.POST("/lists/:kind", p.handleAddToAccessList)
+	ag.DELETE("/lists/:kind/:actor", p.handleRemoveFromAccessList)
+	ag.GET("/status", p.handleAdminStatus)
+	ag.GET("/conversations", p.handleListConversations)
+	ag.POST("/pause", p.handlePause)
+	ag.POST("/resume", p.handleResume)
+	ag.GET("/blocks", p.handleListBlocks)
+	ag.GET("/blocks/:actor", p.handleGetBlock)
+	ag.POST("/blocks/:actor/detach", p.handleDetachBlock)
+	ag.GET("/blocks/:actor/history", p.handleGetBlockHistory)
+	ag.POST("/blocks/:actor/restore/:version", p.handleRestoreBlockVersion)
+	ag.POST("/reset-messages", p.handleResetMessages)
+	ag.POST("/test-reply", p.handleTestReply)
+	ag.POST("/retract", p.handleRetract)
 }
 
 type RequestError struct {
@@ -0,0 +1,136 @@
+package penelope
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/lex/util"
+	"mvdan.cc/xurls/v2"
+)
+
+// splitPostText breaks text up on word boundaries into chunks that will fit inside of a single post
+func splitPostText(text string) []string {
+	postTexts := []string{}
+	var currentText string
+	words := strings.Split(text, " ")
+	for i, w := range words {
+		currentText += w + " "
+		if len(words)-1 == i || len(currentText) >= 250 {
+			postTexts = append(postTexts, currentText)
+			currentText = ""
+		}
+	}
+	return postTexts
+}
+
+// createPostChain writes text as a chain of posts in a single applyWrites call. If parent is nil, the first post
+// will be a top level post and the root of the chain. Returns the strong refs of each created post.
+func (p *Penelope) createPostChain(ctx context.Context, root, parent *atproto.RepoStrongRef, text string) ([]*atproto.RepoStrongRef, error) {
+	return p.createPostChainWithEmbed(ctx, root, parent, text, nil)
+}
+
+// createPostChainWithEmbed is createPostChain with an embed for the first post in the chain. If embed is nil, links
+// in each post are embedded as external link cards.
+func (p *Penelope) createPostChainWithEmbed(ctx context.Context, root, parent *atproto.RepoStrongRef, text string, embed *bsky.FeedPost_Embed) ([]*atproto.RepoStrongRef, error) {
+	var created []*atproto.RepoStrongRef
+	var writes []*atproto.RepoApplyWrites_Input_Writes_Elem
+	for _, pt := range splitPostText(text) {
+		pt = strings.TrimSpace(pt)
+		if pt == "" {
+			continue
+		}
+
+		rkey := p.clock.Next().String()
+		post := bsky.FeedPost{
+			Text:      pt,
+			CreatedAt: syntax.DatetimeNow().String(),
+		}
+
+		if parent != nil {
+			post.Reply = &bsky.FeedPost_ReplyRef{
+				Parent: parent,
+				Root:   root,
+			}
+		}
+
+		strict := xurls.Strict()
+		urls := strict.FindAllString(pt, -1)
+		if embed != nil && len(created) == 0 {
+			post.Embed = embed
+		} else if len(urls) != 0 {
+			post.Embed = &bsky.FeedPost_Embed{
+				EmbedExternal: &bsky.EmbedExternal{
+					External: &bsky.EmbedExternal_External{
+						Uri: urls[0],
+					},
+				},
+			}
+		}
+
+		writes = append(writes, &atproto.RepoApplyWrites_Input_Writes_Elem{
+			RepoApplyWrites_Create: &atproto.RepoApplyWrites_Create{
+				Collection: "app.bsky.feed.post",
+				Rkey:       &rkey,
+				Value:      &util.LexiconTypeDecoder{Val: &post},
+			},
+		})
+
+		cborBytes := new(bytes.Buffer)
+		if err := post.MarshalCBOR(cborBytes); err != nil {
+			return nil, fmt.Errorf("failed to marshal post: %w", err)
+		}
+		cidFromJson, err := cidbuilder.Sum(cborBytes.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cid: %w", err)
+		}
+
+		parent = &atproto.RepoStrongRef{
+			Uri: uriFromParts(p.botDid, "app.bsky.feed.post", rkey),
+			Cid: cidFromJson.String(),
+		}
+		created = append(created, parent)
+
+		if root == nil {
+			root = parent
+		}
+	}
+
+	if len(writes) == 0 {
+		return nil, fmt.Errorf("no text to post")
+	}
+
+	input := &atproto.RepoApplyWrites_Input{
+		Repo:   p.botDid,
+		Writes: writes,
+	}
+
+	if _, err := atproto.RepoApplyWrites(ctx, p.GetClient(), input); err != nil {
+		return nil, err
+	}
+
+	if err := p.recordSentPosts(created); err != nil {
+		p.logger.Error("failed to record sent posts", "error", err)
+	}
+
+	return created, nil
+}
+
+// replyToPost replies to the given post, splitting the text into a chain of replies if needed
+func (p *Penelope) replyToPost(ctx context.Context, rec *bsky.FeedPost, uri, cid, text string) ([]*atproto.RepoStrongRef, error) {
+	parent := &atproto.RepoStrongRef{
+		Uri: uri,
+		Cid: cid,
+	}
+
+	root := parent
+	if rec.Reply != nil && rec.Reply.Root != nil {
+		root = rec.Reply.Root
+	}
+
+	return p.createPostChain(ctx, root, parent, text)
+}
@@ -0,0 +1,174 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+// replyContext holds everything the bot knows from the appview about a post it might reply to
+type replyContext struct {
+	profile *bsky.ActorDefs_ProfileViewDetailed
+	// chain starts at the parent of the post and walks up towards the root. It is empty for top level posts, and
+	// might not reach the root in very deep threads.
+	chain []*bsky.FeedDefs_PostView
+	// unavailable is set to the skip reason if the parent is blocked or missing, or the root is blocked
+	unavailable string
+}
+
+func (p *Penelope) loadReplyContext(ctx context.Context, rec *bsky.FeedPost, did string) (*replyContext, error) {
+	profile, err := bsky.ActorGetProfile(ctx, p.GetClient(), did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	rc := &replyContext{
+		profile: profile,
+	}
+
+	if rec.Reply == nil || rec.Reply.Parent == nil || rec.Reply.Root == nil {
+		return rc, nil
+	}
+
+	height := int64(80)
+	if p.maxThreadDepth > 0 {
+		height = int64(p.maxThreadDepth) + 1
+	}
+
+	chain, unavailable, err := p.loadParentChain(ctx, rec.Reply.Parent.Uri, rec.Reply.Root.Uri, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parent chain: %w", err)
+	}
+	rc.chain = chain
+	rc.unavailable = unavailable
+
+	return rc, nil
+}
+
+// loadParentChain returns the chain of posts starting at the given uri and walking up to the root. The first element
+// of the result is the post at uri itself. If the parent is blocked or missing, or the root is blocked, the matching
+// skip reason is returned. Other blocked or missing ancestors are routine in long threads, so the chain just stops
+// there.
+func (p *Penelope) loadParentChain(ctx context.Context, uri, rootUri string, height int64) ([]*bsky.FeedDefs_PostView, string, error) {
+	resp, err := bsky.FeedGetPostThread(ctx, p.GetClient(), 0, height, uri)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case resp.Thread == nil:
+		return nil, "", fmt.Errorf("thread for %s was not found", uri)
+	case resp.Thread.FeedDefs_BlockedPost != nil:
+		return nil, "blocked", nil
+	case resp.Thread.FeedDefs_NotFoundPost != nil:
+		return nil, "parent-unavailable", nil
+	case resp.Thread.FeedDefs_ThreadViewPost == nil:
+		return nil, "", fmt.Errorf("thread for %s was not found", uri)
+	}
+
+	var chain []*bsky.FeedDefs_PostView
+	tvp := resp.Thread.FeedDefs_ThreadViewPost
+	for tvp != nil {
+		chain = append(chain, tvp.Post)
+		if tvp.Parent == nil {
+			break
+		}
+		if bp := tvp.Parent.FeedDefs_BlockedPost; bp != nil && bp.Uri == rootUri {
+			return chain, "blocked", nil
+		}
+		tvp = tvp.Parent.FeedDefs_ThreadViewPost
+	}
+
+	return chain, "", nil
+}
+
+// root returns the root post of the thread if it was loaded
+func (rc *replyContext) root(rec *bsky.FeedPost) *bsky.FeedDefs_PostView {
+	if len(rc.chain) == 0 || rec.Reply == nil || rec.Reply.Root == nil {
+		return nil
+	}
+	last := rc.chain[len(rc.chain)-1]
+	if last.Uri != rec.Reply.Root.Uri {
+		return nil
+	}
+	return last
+}
+
+// checkReplyAllowed looks at the viewer state of the author and the thread to decide if the bot is allowed to reply.
+// Returns the reason the reply should be skipped, or an empty string if the bot may reply.
+func (p *Penelope) checkReplyAllowed(rc *replyContext, rec *bsky.FeedPost) string {
+	if v := rc.profile.Viewer; v != nil {
+		if (v.BlockedBy != nil && *v.BlockedBy) || v.Blocking != nil || v.BlockingByList != nil {
+			return "blocked"
+		}
+		if (v.Muted != nil && *v.Muted) || v.MutedByList != nil {
+			return "muted"
+		}
+	}
+
+	if rc.unavailable != "" {
+		return rc.unavailable
+	}
+
+	if len(rc.chain) == 0 {
+		return ""
+	}
+
+	if v := rc.chain[0].Viewer; v != nil {
+		if v.ThreadMuted != nil && *v.ThreadMuted {
+			return "thread-muted"
+		}
+		if v.ReplyDisabled != nil {
+			if *v.ReplyDisabled {
+				return "threadgate"
+			}
+			return ""
+		}
+	}
+
+	// the appview didn't tell us if replies are disabled, so check the threadgate ourselves
+	root := rc.root(rec)
+	if root == nil || root.Threadgate == nil || root.Threadgate.Record == nil {
+		return ""
+	}
+
+	tg, ok := root.Threadgate.Record.Val.(*bsky.FeedThreadgate)
+	if !ok || tg.Allow == nil {
+		return ""
+	}
+
+	for _, rule := range tg.Allow {
+		switch {
+		case rule.FeedThreadgate_MentionRule != nil:
+			if rootPost, ok := root.Record.Val.(*bsky.FeedPost); ok && postMentions(rootPost, p.botDid) {
+				return ""
+			}
+		case rule.FeedThreadgate_FollowerRule != nil:
+			if root.Author.Viewer != nil && root.Author.Viewer.Following != nil {
+				return ""
+			}
+		case rule.FeedThreadgate_FollowingRule != nil:
+			if root.Author.Viewer != nil && root.Author.Viewer.FollowedBy != nil {
+				return ""
+			}
+		case rule.FeedThreadgate_ListRule != nil:
+			// we can't cheaply check list membership, so let the appview's decision stand
+			return ""
+		}
+	}
+
+	return "threadgate"
+}
+
+// postMentions returns whether the post has a mention facet for the given did
+func postMentions(rec *bsky.FeedPost, did string) bool {
+	for _, f := range rec.Facets {
+		for _, ff := range f.Features {
+			if ff.RichtextFacet_Mention != nil && ff.RichtextFacet_Mention.Did == did {
+				return true
+			}
+		}
+	}
+	return false
+}
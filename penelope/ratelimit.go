@@ -0,0 +1,125 @@
+package penelope
+
+import (
+	"context"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	globalRateLimitKey = "global"
+	slowDownText       = "I'm getting a lot of messages from you right now, so I'm going to take a short break from replying. Try again in a little while!"
+)
+
+type RateLimitConfig struct {
+	Burst  int
+	Refill time.Duration
+}
+
+func (c RateLimitConfig) enabled() bool {
+	return c.Burst > 0 && c.Refill > 0
+}
+
+// takeToken attempts to take a token from the bucket for the given key, refilling the bucket based on the time since
+// it was last used. The bucket is persisted so that restarts don't reset any limits. If the bucket is empty, notify
+// will be true only the first time the bucket is found empty since it was last allowed.
+func (p *Penelope) takeToken(key string, cfg RateLimitConfig) (allowed bool, notify bool, err error) {
+	p.rateLimitMu.Lock()
+	defer p.rateLimitMu.Unlock()
+
+	now := time.Now()
+
+	var bucket RateLimitBucket
+	res := p.db.Where("key = ?", key).Limit(1).Find(&bucket)
+	if res.Error != nil {
+		return false, false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		bucket = RateLimitBucket{
+			Key:        key,
+			Tokens:     float64(cfg.Burst),
+			LastRefill: now,
+		}
+	}
+
+	bucket.Tokens += float64(now.Sub(bucket.LastRefill)) / float64(cfg.Refill)
+	if bucket.Tokens > float64(cfg.Burst) {
+		bucket.Tokens = float64(cfg.Burst)
+	}
+	bucket.LastRefill = now
+
+	if bucket.Tokens >= 1 {
+		bucket.Tokens--
+		bucket.Notified = false
+		allowed = true
+	} else if !bucket.Notified {
+		bucket.Notified = true
+		notify = true
+	}
+
+	if err := p.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		UpdateAll: true,
+	}).Create(&bucket).Error; err != nil {
+		return false, false, err
+	}
+
+	return allowed, notify, nil
+}
+
+// refundToken puts back a token taken with takeToken, for when the reply it was taken for didn't happen
+func (p *Penelope) refundToken(key string, cfg RateLimitConfig) error {
+	p.rateLimitMu.Lock()
+	defer p.rateLimitMu.Unlock()
+
+	return p.db.Model(&RateLimitBucket{}).Where("key = ?", key).Update("tokens", gorm.Expr("MIN(tokens + 1, ?)", cfg.Burst)).Error
+}
+
+// checkRateLimits returns whether the bot is allowed to reply to the given post. If the author has just hit their
+// limit, they'll get a single reply letting them know to slow down.
+func (p *Penelope) checkRateLimits(ctx context.Context, rec *bsky.FeedPost, did, uri, cid string) (bool, error) {
+	if p.userRateLimit.enabled() {
+		allowed, notify, err := p.takeToken("user-"+did, p.userRateLimit)
+		if err != nil {
+			return false, err
+		}
+
+		if !allowed {
+			throttledEvents.WithLabelValues("user").Inc()
+			p.logger.Info("user is being rate limited", "did", did, "uri", uri)
+
+			if notify {
+				if _, err := p.replyToPost(ctx, rec, uri, cid, slowDownText); err != nil {
+					p.logger.Error("could not send slow down reply", "error", err)
+				}
+			}
+
+			return false, nil
+		}
+	}
+
+	if p.globalRateLimit.enabled() {
+		allowed, _, err := p.takeToken(globalRateLimitKey, p.globalRateLimit)
+		if err != nil {
+			return false, err
+		}
+
+		if !allowed {
+			// the user shouldn't pay for a reply that the global limit dropped
+			if p.userRateLimit.enabled() {
+				if err := p.refundToken("user-"+did, p.userRateLimit); err != nil {
+					p.logger.Error("failed to refund user rate limit token", "did", did, "error", err)
+				}
+			}
+
+			throttledEvents.WithLabelValues("global").Inc()
+			p.logger.Warn("global rate limit reached, dropping reply", "did", did, "uri", uri)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,150 @@
+package penelope
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+func TestTakeToken(t *testing.T) {
+	type take struct {
+		// rewind moves the bucket's last refill back by this much before taking, to simulate time passing
+		rewind  time.Duration
+		allowed bool
+		notify  bool
+	}
+
+	cfg := RateLimitConfig{Burst: 2, Refill: time.Minute}
+
+	tests := []struct {
+		name  string
+		takes []take
+	}{
+		{
+			name: "burst is allowed",
+			takes: []take{
+				{allowed: true},
+				{allowed: true},
+			},
+		},
+		{
+			name: "notifies once when empty",
+			takes: []take{
+				{allowed: true},
+				{allowed: true},
+				{allowed: false, notify: true},
+				{allowed: false, notify: false},
+				{allowed: false, notify: false},
+			},
+		},
+		{
+			name: "refills over time",
+			takes: []take{
+				{allowed: true},
+				{allowed: true},
+				{allowed: false, notify: true},
+				{rewind: time.Minute, allowed: true},
+				{allowed: false, notify: true},
+			},
+		},
+		{
+			name: "refill is capped at burst",
+			takes: []take{
+				{allowed: true},
+				{rewind: time.Hour, allowed: true},
+				{allowed: true},
+				{allowed: false, notify: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := openDB(filepath.Join(t.TempDir(), "penelope.db"))
+			if err != nil {
+				t.Fatalf("failed to open db: %v", err)
+			}
+			p := &Penelope{db: db}
+
+			for i, tk := range tt.takes {
+				if tk.rewind > 0 {
+					if err := db.Model(&RateLimitBucket{}).Where("key = ?", "test").Update("last_refill", time.Now().Add(-tk.rewind)).Error; err != nil {
+						t.Fatalf("failed to rewind bucket: %v", err)
+					}
+				}
+
+				allowed, notify, err := p.takeToken("test", cfg)
+				if err != nil {
+					t.Fatalf("take %d: unexpected error: %v", i, err)
+				}
+				if allowed != tk.allowed || notify != tk.notify {
+					t.Errorf("take %d: got allowed=%v notify=%v, want allowed=%v notify=%v", i, allowed, notify, tk.allowed, tk.notify)
+				}
+			}
+		})
+	}
+}
+
+func TestTakeTokenKeysAreIndependent(t *testing.T) {
+	db, err := openDB(filepath.Join(t.TempDir(), "penelope.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	p := &Penelope{db: db}
+
+	cfg := RateLimitConfig{Burst: 1, Refill: time.Hour}
+
+	if allowed, _, _ := p.takeToken("user-a", cfg); !allowed {
+		t.Fatalf("first take for user-a was not allowed")
+	}
+	if allowed, _, _ := p.takeToken("user-a", cfg); allowed {
+		t.Fatalf("second take for user-a was allowed")
+	}
+	if allowed, _, _ := p.takeToken("user-b", cfg); !allowed {
+		t.Fatalf("first take for user-b was not allowed")
+	}
+}
+
+func TestCheckRateLimitsRefundsUserWhenGloballyLimited(t *testing.T) {
+	db, err := openDB(filepath.Join(t.TempDir(), "penelope.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	p := &Penelope{
+		db:              db,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		userRateLimit:   RateLimitConfig{Burst: 2, Refill: time.Hour},
+		globalRateLimit: RateLimitConfig{Burst: 1, Refill: time.Hour},
+	}
+
+	tests := []struct {
+		did     string
+		allowed bool
+	}{
+		{did: "did:plc:alice", allowed: true},
+		{did: "did:plc:alice", allowed: false},
+		{did: "did:plc:alice", allowed: false},
+	}
+	for i, tt := range tests {
+		allowed, err := p.checkRateLimits(context.Background(), &bsky.FeedPost{}, tt.did, "", "")
+		if err != nil {
+			t.Fatalf("check %d: unexpected error: %v", i, err)
+		}
+		if allowed != tt.allowed {
+			t.Errorf("check %d: got allowed=%v, want %v", i, allowed, tt.allowed)
+		}
+	}
+
+	var bucket RateLimitBucket
+	if err := db.Where("key = ?", "user-did:plc:alice").Take(&bucket).Error; err != nil {
+		t.Fatalf("failed to get user bucket: %v", err)
+	}
+	if bucket.Tokens < 0.99 || bucket.Tokens > 1.01 {
+		t.Errorf("user bucket has %v tokens, want 1", bucket.Tokens)
+	}
+}
@@ -0,0 +1,143 @@
+package penelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/xrpc"
+	"gorm.io/gorm"
+)
+
+// recordSentPosts stores the posts created by a single applyWrites call so the whole batch can be retracted later
+func (p *Penelope) recordSentPosts(refs []*atproto.RepoStrongRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	aturi, err := syntax.ParseATURI(refs[0].Uri)
+	if err != nil {
+		return err
+	}
+	batchId := aturi.RecordKey().String()
+
+	var posts []SentPost
+	for i, ref := range refs {
+		posts = append(posts, SentPost{
+			Uri:      ref.Uri,
+			Cid:      ref.Cid,
+			BatchId:  batchId,
+			Position: i,
+		})
+	}
+
+	return p.db.Create(&posts).Error
+}
+
+// parsePostUri accepts either the at-uri of a post or a bsky.app post url and returns the at-uri
+func (p *Penelope) parsePostUri(ctx context.Context, s string) (syntax.ATURI, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "https://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return "", err
+		}
+		// https://bsky.app/profile/<actor>/post/<rkey>
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) != 4 || parts[0] != "profile" || parts[2] != "post" {
+			return "", fmt.Errorf("not a post url")
+		}
+		did, err := p.resolveActor(ctx, parts[1])
+		if err != nil {
+			return "", err
+		}
+		s = uriFromParts(did, "app.bsky.feed.post", parts[3])
+	}
+
+	aturi, err := syntax.ParseATURI(s)
+	if err != nil {
+		return "", err
+	}
+
+	if aturi.Collection().String() != "app.bsky.feed.post" {
+		return "", fmt.Errorf("not a post uri")
+	}
+
+	return aturi, nil
+}
+
+// isRecordNotFound returns whether the error is the pds saying that a record doesn't exist
+func isRecordNotFound(err error) bool {
+	var xe *xrpc.XRPCError
+	return errors.As(err, &xe) && xe.ErrStr == "RecordNotFound"
+}
+
+// retractPostBatch deletes every post that was created in the same batch as the given post, such as every reply in a
+// split reply chain. Posts in the batch that have already been deleted on their own are skipped, since a single
+// missing record would fail the whole applyWrites call. Returns the uris of the deleted posts.
+func (p *Penelope) retractPostBatch(ctx context.Context, actor, uri string) ([]string, error) {
+	var sent []SentPost
+	if err := p.db.Where("uri = ?", uri).Limit(1).Find(&sent).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sent post: %w", err)
+	}
+	if len(sent) == 0 {
+		return nil, fmt.Errorf("no record of sending this post")
+	}
+
+	var batch []SentPost
+	if err := p.db.Where("batch_id = ?", sent[0].BatchId).Order("position").Find(&batch).Error; err != nil {
+		return nil, fmt.Errorf("failed to get post batch: %w", err)
+	}
+
+	var writes []*atproto.RepoApplyWrites_Input_Writes_Elem
+	var uris []string
+	for _, post := range batch {
+		aturi, err := syntax.ParseATURI(post.Uri)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := atproto.RepoGetRecord(ctx, p.GetClient(), "", aturi.Collection().String(), p.botDid, aturi.RecordKey().String()); err != nil {
+			if isRecordNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get post %s: %w", post.Uri, err)
+		}
+		writes = append(writes, &atproto.RepoApplyWrites_Input_Writes_Elem{
+			RepoApplyWrites_Delete: &atproto.RepoApplyWrites_Delete{
+				Collection: aturi.Collection().String(),
+				Rkey:       aturi.RecordKey().String(),
+			},
+		})
+		uris = append(uris, post.Uri)
+	}
+
+	if len(writes) > 0 {
+		if _, err := atproto.RepoApplyWrites(ctx, p.GetClient(), &atproto.RepoApplyWrites_Input{
+			Repo:   p.botDid,
+			Writes: writes,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to delete posts: %w", err)
+		}
+	}
+
+	if err := p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("batch_id = ?", sent[0].BatchId).Delete(&SentPost{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&AuditEntry{
+			Actor:   actor,
+			Action:  "retract",
+			Subject: uri,
+			Detail:  strings.Join(uris, "\n"),
+		}).Error
+	}); err != nil {
+		p.logger.Error("failed to record retraction", "uri", uri, "error", err)
+	}
+
+	return uris, nil
+}
@@ -0,0 +1,51 @@
+package penelope
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bluesky-social/indigo/xrpc"
+)
+
+func TestIsRecordNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "record not found",
+			err:  &xrpc.Error{StatusCode: 400, Wrapped: &xrpc.XRPCError{ErrStr: "RecordNotFound", Message: "Could not locate record"}},
+			want: true,
+		},
+		{
+			name: "wrapped record not found",
+			err:  fmt.Errorf("failed: %w", &xrpc.Error{StatusCode: 400, Wrapped: &xrpc.XRPCError{ErrStr: "RecordNotFound"}}),
+			want: true,
+		},
+		{
+			name: "other xrpc error",
+			err:  &xrpc.Error{StatusCode: 400, Wrapped: &xrpc.XRPCError{ErrStr: "InvalidRequest"}},
+			want: false,
+		},
+		{
+			name: "server error",
+			err:  &xrpc.Error{StatusCode: 500},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRecordNotFound(tt.err); got != tt.want {
+				t.Errorf("isRecordNotFound(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+const (
+	SocialActionLike     = "like"
+	SocialActionUnlike   = "unlike"
+	SocialActionRepost   = "repost"
+	SocialActionQuote    = "quote"
+	SocialActionFollow   = "follow"
+	SocialActionUnfollow = "unfollow"
+)
+
+// DefaultSocialActionLimits are the number of times per day the agent may take each action. Actions that aren't listed
+// are not limited.
+var DefaultSocialActionLimits = map[string]int{
+	SocialActionLike:   100,
+	SocialActionRepost: 25,
+	SocialActionQuote:  10,
+	SocialActionFollow: 25,
+}
+
+// ParseSocialActionLimits parses entries in the form of action=N on top of the default limits. A limit of 0 disables the
+// action entirely.
+func ParseSocialActionLimits(entries []string) (map[string]int, error) {
+	limits := map[string]int{}
+	for k, v := range DefaultSocialActionLimits {
+		limits[k] = v
+	}
+
+	for _, e := range entries {
+		action, limit, ok := strings.Cut(e, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid social action limit %q, expected action=N", e)
+		}
+
+		action = strings.TrimSpace(action)
+		switch action {
+		case SocialActionLike, SocialActionUnlike, SocialActionRepost, SocialActionQuote, SocialActionFollow, SocialActionUnfollow:
+		default:
+			return nil, fmt.Errorf("invalid social action %q", action)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit %q for social action %q", limit, action)
+		}
+
+		limits[action] = n
+	}
+
+	return limits, nil
+}
+
+var errSocialActionLimited = fmt.Errorf("daily limit reached")
+
+// checkSocialAction returns an error if the agent has already taken the given action as many times as it is allowed
+// to in the last 24 hours
+func (p *Penelope) checkSocialAction(action string) error {
+	limit, ok := p.socialActionLimits[action]
+	if !ok {
+		return nil
+	}
+
+	if limit == 0 {
+		return fmt.Errorf("%s is disabled", action)
+	}
+
+	var count int64
+	if err := p.db.Model(&SocialAction{}).Where("action = ? AND created_at > ?", action, time.Now().Add(-24*time.Hour)).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count >= int64(limit) {
+		throttledEvents.WithLabelValues("social-" + action).Inc()
+		return errSocialActionLimited
+	}
+
+	return nil
+}
+
+// recordSocialAction records that the agent successfully took an action, so that it counts towards the daily limit
+func (p *Penelope) recordSocialAction(action string) {
+	socialActions.WithLabelValues(action).Inc()
+
+	if err := p.db.Create(&SocialAction{Action: action}).Error; err != nil {
+		p.logger.Error("failed to record social action", "action", action, "error", err)
+	}
+}
+
+// getPostView fetches the appview's view of a post, including the bot's viewer state for it
+func (p *Penelope) getPostView(ctx context.Context, uri string) (*bsky.FeedDefs_PostView, error) {
+	aturi, err := syntax.ParseATURI(uri)
+	if err != nil || aturi.Collection().String() != "app.bsky.feed.post" {
+		return nil, fmt.Errorf("uri must be the at-uri of a post")
+	}
+
+	resp, err := bsky.FeedGetPosts(ctx, p.GetClient(), []string{uri})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	if len(resp.Posts) == 0 {
+		return nil, fmt.Errorf("post not found")
+	}
+
+	return resp.Posts[0], nil
+}
+
+// createOwnRecord writes a record to the bot's repo and returns its uri
+func (p *Penelope) createOwnRecord(ctx context.Context, collection string, rec *atproto.RepoCreateRecord_Input) (string, error) {
+	rkey := p.clock.Next().String()
+	rec.Collection = collection
+	rec.Repo = p.botDid
+	rec.Rkey = &rkey
+
+	out, err := atproto.RepoCreateRecord(ctx, p.GetClient(), rec)
+	if err != nil {
+		return "", err
+	}
+
+	return out.Uri, nil
+}
+
+// deleteOwnRecord deletes a record from the bot's repo. Records in any other repo are rejected.
+func (p *Penelope) deleteOwnRecord(ctx context.Context, uri string) error {
+	aturi, err := syntax.ParseATURI(uri)
+	if err != nil {
+		return err
+	}
+
+	if aturi.Authority().String() != p.botDid {
+		return fmt.Errorf("record is not in the bot's repo")
+	}
+
+	if _, err := atproto.RepoDeleteRecord(ctx, p.GetClient(), &atproto.RepoDeleteRecord_Input{
+		Collection: aturi.Collection().String(),
+		Repo:       p.botDid,
+		Rkey:       aturi.RecordKey().String(),
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
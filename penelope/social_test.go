@@ -0,0 +1,56 @@
+package penelope
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckSocialAction(t *testing.T) {
+	db, err := openDB(filepath.Join(t.TempDir(), "penelope.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	p := &Penelope{
+		db:     db,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		socialActionLimits: map[string]int{
+			SocialActionLike:   2,
+			SocialActionFollow: 0,
+		},
+	}
+
+	// an action from over a day ago doesn't count towards the limit
+	if err := db.Create(&SocialAction{Action: SocialActionLike}).Error; err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+	if err := db.Model(&SocialAction{}).Where("1 = 1").Update("created_at", time.Now().Add(-25*time.Hour)).Error; err != nil {
+		t.Fatalf("failed to age action: %v", err)
+	}
+
+	for i := range 2 {
+		if err := p.checkSocialAction(SocialActionLike); err != nil {
+			t.Fatalf("like %d: unexpected error: %v", i, err)
+		}
+		// checking alone doesn't use up the limit
+		if err := p.checkSocialAction(SocialActionLike); err != nil {
+			t.Fatalf("like %d: unexpected error on second check: %v", i, err)
+		}
+		p.recordSocialAction(SocialActionLike)
+	}
+
+	if err := p.checkSocialAction(SocialActionLike); !errors.Is(err, errSocialActionLimited) {
+		t.Errorf("got %v after reaching the limit, want %v", err, errSocialActionLimited)
+	}
+
+	if err := p.checkSocialAction(SocialActionFollow); err == nil || errors.Is(err, errSocialActionLimited) {
+		t.Errorf("got %v for a disabled action, want a disabled error", err)
+	}
+
+	if err := p.checkSocialAction(SocialActionUnlike); err != nil {
+		t.Errorf("got %v for an unlimited action, want nil", err)
+	}
+}
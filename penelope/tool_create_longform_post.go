@@ -0,0 +1,89 @@
+package penelope
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+type CreateLongformPostInput struct {
+	// Platform selects which long form lexicon the post is written with. Defaults to whitewind.
+	Platform   string               `json:"platform"`
+	Title      string               `json:"title"`
+	Subtitle   string               `json:"subtitle"`
+	Text       string               `json:"text"`
+	Visibility string               `json:"visibility"`
+	Images     []LongformImageInput `json:"images"`
+
+	// Announce publishes a bluesky post linking to the new entry. If AnnounceReplyTo is set, the announcement is
+	// posted as a reply to that post instead of as a top level post.
+	Announce        bool   `json:"announce"`
+	AnnounceText    string `json:"announce_text"`
+	AnnounceReplyTo string `json:"announce_reply_to"`
+}
+
+type CreateLongformPostResponse struct {
+	Url             string `json:"url"`
+	Uri             string `json:"uri"`
+	Cid             string `json:"cid"`
+	AnnouncementUri string `json:"announcement_uri,omitempty"`
+	AnnouncementErr string `json:"announcement_error,omitempty"`
+}
+
+func (p *Penelope) handleCreateLongformPost(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input CreateLongformPostInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	if input.Platform == "" {
+		input.Platform = LongformPlatformWhitewind
+	}
+
+	publisher, ok := p.longformPublishers[input.Platform]
+	if !ok {
+		return e.JSON(400, makeErrorJson("unsupported platform "+input.Platform))
+	}
+
+	if input.Platform == LongformPlatformWhitewind {
+		if input.Visibility == "" {
+			input.Visibility = WhitewindVisibilityUrl
+		}
+
+		if input.Announce && input.Visibility == WhitewindVisibilityAuthor {
+			return e.JSON(400, makeErrorJson("cannot announce an entry that is only visible to the author"))
+		}
+	}
+
+	entry, err := publisher.Publish(ctx, LongformDraft{
+		Title:      input.Title,
+		Subtitle:   input.Subtitle,
+		Content:    input.Text,
+		Visibility: input.Visibility,
+		Images:     input.Images,
+	})
+	if err != nil {
+		p.logger.Error("failed to create long form post", "platform", input.Platform, "error", err)
+		return e.JSON(500, makeErrorJson("failed to create long form post: "+err.Error()))
+	}
+
+	resp := CreateLongformPostResponse{
+		Url: entry.Url,
+		Uri: entry.Uri,
+		Cid: entry.Cid,
+	}
+
+	// the entry has already been written at this point, so a failed announcement is reported alongside it rather
+	// than failing the whole request
+	if input.Announce {
+		announcement, err := p.announceLongformPost(ctx, entry, input.AnnounceText, input.AnnounceReplyTo)
+		if err != nil {
+			p.logger.Error("failed to announce long form post", "uri", entry.Uri, "error", err)
+			resp.AnnouncementErr = err.Error()
+		} else {
+			resp.AnnouncementUri = announcement.Uri
+		}
+	}
+
+	return e.JSON(200, resp)
+}
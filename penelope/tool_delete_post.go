@@ -0,0 +1,40 @@
+package penelope
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+type DeletePostInput struct {
+	Uri string `json:"uri"`
+}
+
+func (p *Penelope) handleDeletePost(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input DeletePostInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	aturi, err := p.parsePostUri(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("uri must be the at-uri of a post"))
+	}
+
+	if aturi.Authority().String() != p.botDid {
+		return e.JSON(403, makeErrorJson("can only delete your own posts"))
+	}
+
+	if err := p.deleteOwnRecord(ctx, aturi.String()); err != nil {
+		p.logger.Error("failed to delete post", "uri", aturi.String(), "error", err)
+		return e.JSON(500, makeErrorJson("failed to delete post"))
+	}
+
+	if err := p.db.Where("uri = ?", aturi.String()).Delete(&SentPost{}).Error; err != nil {
+		p.logger.Error("failed to remove sent post", "uri", aturi.String(), "error", err)
+	}
+
+	p.logger.Info("deleted post from tool", "uri", aturi.String())
+
+	return e.NoContent(200)
+}
@@ -0,0 +1,180 @@
+package penelope
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/labstack/echo/v4"
+)
+
+type GetProfileInput struct {
+	Actor string `json:"actor"`
+}
+
+type GetProfileResponse struct {
+	Profile string `json:"profile"`
+}
+
+func (p *Penelope) handleGetProfile(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input GetProfileInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	did, err := p.resolveActor(ctx, input.Actor)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid actor"))
+	}
+
+	if p.isIgnored(did) {
+		return e.JSON(400, makeErrorJson("cannot look up this user"))
+	}
+
+	profile, err := bsky.ActorGetProfile(ctx, p.GetClient(), did)
+	if err != nil {
+		p.logger.Error("failed to get profile", "did", did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get profile"))
+	}
+
+	var displayName, description, createdAt string
+	if profile.DisplayName != nil {
+		displayName = *profile.DisplayName
+	}
+	if profile.Description != nil {
+		description = *profile.Description
+	}
+	if profile.CreatedAt != nil {
+		createdAt = *profile.CreatedAt
+	}
+
+	var labels []string
+	for _, l := range activeLabels(profile.Labels) {
+		labels = append(labels, l.Val)
+	}
+
+	followsYou := profile.Viewer != nil && profile.Viewer.FollowedBy != nil
+	youFollow := profile.Viewer != nil && profile.Viewer.Following != nil
+
+	var sb strings.Builder
+	sb.WriteString("<BEGIN PROFILE>\n")
+	fmt.Fprintf(&sb, "handle: @%s\n", profile.Handle)
+	fmt.Fprintf(&sb, "did: %s\n", profile.Did)
+	fmt.Fprintf(&sb, "name: %s\n", displayName)
+	fmt.Fprintf(&sb, "bio: %s\n", description)
+	fmt.Fprintf(&sb, "joined: %s\n", createdAt)
+	fmt.Fprintf(&sb, "followers: %d\n", derefInt(profile.FollowersCount))
+	fmt.Fprintf(&sb, "following: %d\n", derefInt(profile.FollowsCount))
+	fmt.Fprintf(&sb, "posts: %d\n", derefInt(profile.PostsCount))
+	fmt.Fprintf(&sb, "labels: %s\n", strings.Join(labels, ", "))
+	fmt.Fprintf(&sb, "follows you: %t\n", followsYou)
+	fmt.Fprintf(&sb, "you follow them: %t\n", youFollow)
+	sb.WriteString("<END PROFILE>")
+
+	return e.JSON(200, GetProfileResponse{
+		Profile: sb.String(),
+	})
+}
+
+type GetRelationshipInput struct {
+	// Actor is the user to look up. If Other is empty, the relationship is between Actor and the bot.
+	Actor string `json:"actor"`
+	Other string `json:"other"`
+}
+
+type GetRelationshipResponse struct {
+	Relationship string `json:"relationship"`
+}
+
+func (p *Penelope) handleGetRelationship(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input GetRelationshipInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	did, err := p.resolveActor(ctx, input.Actor)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid actor"))
+	}
+
+	other := p.botDid
+	if input.Other != "" {
+		other, err = p.resolveActor(ctx, input.Other)
+		if err != nil {
+			return e.JSON(400, makeErrorJson("invalid other actor"))
+		}
+	}
+
+	if did == other {
+		return e.JSON(400, makeErrorJson("actor and other must be different users"))
+	}
+
+	resp, err := bsky.GraphGetRelationships(ctx, p.GetClient(), did, []string{other})
+	if err != nil {
+		p.logger.Error("failed to get relationships", "did", did, "other", other, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get relationship"))
+	}
+
+	var following, followedBy bool
+	for _, r := range resp.Relationships {
+		if r.GraphDefs_NotFoundActor != nil {
+			return e.JSON(404, makeErrorJson("user not found"))
+		}
+		if r.GraphDefs_Relationship != nil && r.GraphDefs_Relationship.Did == other {
+			following = r.GraphDefs_Relationship.Following != nil
+			followedBy = r.GraphDefs_Relationship.FollowedBy != nil
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<BEGIN RELATIONSHIP>\n")
+	fmt.Fprintf(&sb, "actor: %s\n", did)
+	fmt.Fprintf(&sb, "other: %s\n", other)
+	fmt.Fprintf(&sb, "actor follows other: %t\n", following)
+	fmt.Fprintf(&sb, "other follows actor: %t\n", followedBy)
+	fmt.Fprintf(&sb, "mutuals: %t\n", following && followedBy)
+
+	// blocks and list membership are only visible from the bot's own point of view
+	if other == p.botDid {
+		profile, err := bsky.ActorGetProfile(ctx, p.GetClient(), did)
+		if err != nil {
+			p.logger.Error("failed to get profile", "did", did, "error", err)
+			return e.JSON(500, makeErrorJson("failed to get relationship"))
+		}
+
+		var blocking, blockedBy, muted bool
+		var lists []string
+		if v := profile.Viewer; v != nil {
+			blocking = v.Blocking != nil
+			blockedBy = v.BlockedBy != nil && *v.BlockedBy
+			muted = v.Muted != nil && *v.Muted
+			if v.BlockingByList != nil {
+				lists = append(lists, "blocked by list "+v.BlockingByList.Name)
+			}
+			if v.MutedByList != nil {
+				lists = append(lists, "muted by list "+v.MutedByList.Name)
+			}
+		}
+		if p.isIgnored(did) {
+			lists = append(lists, "on your ignore list")
+		}
+		if p.isAdmin(did) {
+			lists = append(lists, "on your admin list")
+		}
+
+		fmt.Fprintf(&sb, "you block them: %t\n", blocking)
+		fmt.Fprintf(&sb, "they block you: %t\n", blockedBy)
+		fmt.Fprintf(&sb, "you muted them: %t\n", muted)
+		fmt.Fprintf(&sb, "lists: %s\n", strings.Join(lists, ", "))
+	}
+
+	sb.WriteString("<END RELATIONSHIP>")
+
+	return e.JSON(200, GetRelationshipResponse{
+		Relationship: sb.String(),
+	})
+}
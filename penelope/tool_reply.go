@@ -0,0 +1,60 @@
+package penelope
+
+import (
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/labstack/echo/v4"
+)
+
+type ReplyInput struct {
+	Uri  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type ReplyResponse struct {
+	Uris []string `json:"uris"`
+}
+
+func (p *Penelope) handleReply(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input ReplyInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	aturi, err := syntax.ParseATURI(input.Uri)
+	if err != nil || aturi.Collection().String() != "app.bsky.feed.post" {
+		return e.JSON(400, makeErrorJson("uri must be the at-uri of a post"))
+	}
+
+	if strings.TrimSpace(input.Text) == "" {
+		return e.JSON(400, makeErrorJson("text is empty"))
+	}
+
+	if did, err := aturi.Authority().AsDID(); err == nil && p.isIgnored(did.String()) {
+		return e.JSON(400, makeErrorJson("cannot reply to this user"))
+	}
+
+	root, parent, err := p.replyRefs(ctx, input.Uri)
+	if err != nil {
+		p.logger.Error("failed to resolve reply refs", "uri", input.Uri, "error", err)
+		return e.JSON(500, makeErrorJson("failed to resolve post: "+err.Error()))
+	}
+
+	refs, err := p.createPostChain(ctx, root, parent, input.Text)
+	if err != nil {
+		p.logger.Error("failed to create reply", "uri", input.Uri, "error", err)
+		return e.JSON(500, makeErrorJson("failed to create reply"))
+	}
+
+	var uris []string
+	for _, ref := range refs {
+		uris = append(uris, ref.Uri)
+	}
+
+	return e.JSON(200, ReplyResponse{
+		Uris: uris,
+	})
+}
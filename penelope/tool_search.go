@@ -0,0 +1,265 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/haileyok/photocopy/models"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultSearchLimit = 25
+	maxSearchLimit     = 100
+
+	// full text scans over clickhouse are limited to recent posts unless a start date is given
+	defaultClickhouseSearchWindow = 30 * 24 * time.Hour
+)
+
+type SearchPostsInput struct {
+	Query  string `json:"query"`
+	Author string `json:"author"`
+	Since  string `json:"since"`
+	Until  string `json:"until"`
+	Limit  int    `json:"limit"`
+}
+
+type SearchPostsResponse struct {
+	Posts  string `json:"posts"`
+	Source string `json:"source"`
+}
+
+type searchPostsQuery struct {
+	Terms []string
+	Did   string
+	Since time.Time
+	Until time.Time
+	Limit int
+}
+
+type searchResult struct {
+	Uri       string
+	Author    string
+	CreatedAt string
+	Text      string
+}
+
+func (p *Penelope) handleSearchPosts(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input SearchPostsInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	q := searchPostsQuery{
+		Terms: strings.Fields(input.Query),
+		Limit: input.Limit,
+	}
+
+	if len(q.Terms) == 0 {
+		return e.JSON(400, makeErrorJson("query is empty"))
+	}
+
+	if q.Limit <= 0 || q.Limit > maxSearchLimit {
+		q.Limit = defaultSearchLimit
+	}
+
+	if input.Author != "" {
+		did, err := p.resolveActor(ctx, input.Author)
+		if err != nil {
+			return e.JSON(400, makeErrorJson("invalid author"))
+		}
+		q.Did = did
+	}
+
+	if input.Since != "" {
+		since, err := dateparse.ParseAny(input.Since)
+		if err != nil {
+			return e.JSON(400, makeErrorJson("invalid since"))
+		}
+		q.Since = since
+	}
+
+	if input.Until != "" {
+		until, err := dateparse.ParseAny(input.Until)
+		if err != nil {
+			return e.JSON(400, makeErrorJson("invalid until"))
+		}
+		q.Until = until
+	}
+
+	results, source, err := p.searchPosts(ctx, q, input.Query)
+	if err != nil {
+		p.logger.Error("failed to search posts", "error", err)
+		return e.JSON(500, makeErrorJson("failed to search posts"))
+	}
+
+	var postsText string
+	postsText += "<BEGIN POSTS>\n"
+	for _, r := range results {
+		if p.isIgnored(r.Author) {
+			continue
+		}
+		postsText += fmt.Sprintf("<BEGIN POST>uri: %s\nby: %s\nat: %s\n%s<END POST>\n", r.Uri, r.Author, r.CreatedAt, r.Text)
+	}
+	postsText += "<END POSTS>"
+
+	return e.JSON(200, SearchPostsResponse{
+		Posts:  postsText,
+		Source: source,
+	})
+}
+
+// searchPosts searches clickhouse first, falling back to the appview's search if clickhouse is unavailable or has no
+// matching posts. Returns the results and where they came from.
+func (p *Penelope) searchPosts(ctx context.Context, q searchPostsQuery, query string) ([]searchResult, string, error) {
+	results, err := p.searchPostsClickhouse(ctx, q)
+	if err != nil {
+		p.logger.Warn("clickhouse search failed, falling back to appview", "error", err)
+	} else if len(results) > 0 {
+		return results, "clickhouse", nil
+	}
+
+	results, err = p.searchPostsAppview(ctx, q, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return results, "appview", nil
+}
+
+func (p *Penelope) searchPostsClickhouse(ctx context.Context, q searchPostsQuery) ([]searchResult, error) {
+	if p.conn == nil {
+		return nil, fmt.Errorf("clickhouse is not configured")
+	}
+
+	var where []string
+	var args []any
+	for _, term := range q.Terms {
+		where = append(where, "positionCaseInsensitiveUTF8(text, ?) > 0")
+		args = append(args, term)
+	}
+	if q.Did != "" {
+		where = append(where, "did = ?")
+		args = append(args, q.Did)
+	}
+	since := q.Since
+	if since.IsZero() {
+		since = time.Now().Add(-defaultClickhouseSearchWindow)
+	}
+	where = append(where, "created_at >= ?")
+	args = append(args, since)
+	if !q.Until.IsZero() {
+		where = append(where, "created_at <= ?")
+		args = append(args, q.Until)
+	}
+	args = append(args, q.Limit)
+
+	var posts []models.Post
+	if err := p.conn.Select(ctx, &posts, "SELECT * FROM default.post WHERE "+strings.Join(where, " AND ")+" ORDER BY created_at DESC LIMIT ?", args...); err != nil {
+		return nil, err
+	}
+
+	var results []searchResult
+	for _, post := range posts {
+		results = append(results, searchResult{
+			Uri:       post.Uri,
+			Author:    post.Did,
+			CreatedAt: post.CreatedAt.UTC().Format(time.RFC3339),
+			Text:      post.Text,
+		})
+	}
+
+	return results, nil
+}
+
+func (p *Penelope) searchPostsAppview(ctx context.Context, q searchPostsQuery, query string) ([]searchResult, error) {
+	var since, until string
+	if !q.Since.IsZero() {
+		since = q.Since.UTC().Format(time.RFC3339)
+	}
+	if !q.Until.IsZero() {
+		until = q.Until.UTC().Format(time.RFC3339)
+	}
+
+	resp, err := bsky.FeedSearchPosts(ctx, p.GetClient(), q.Did, "", "", "", int64(q.Limit), "", query, since, "latest", nil, until, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var results []searchResult
+	for _, post := range resp.Posts {
+		fp, ok := post.Record.Val.(*bsky.FeedPost)
+		if !ok {
+			continue
+		}
+		results = append(results, searchResult{
+			Uri:       post.Uri,
+			Author:    post.Author.Did,
+			CreatedAt: fp.CreatedAt,
+			Text:      fp.Text,
+		})
+	}
+
+	return results, nil
+}
+
+type SearchUsersInput struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+type SearchUsersResponse struct {
+	Users string `json:"users"`
+}
+
+func (p *Penelope) handleSearchUsers(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input SearchUsersInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	if strings.TrimSpace(input.Query) == "" {
+		return e.JSON(400, makeErrorJson("query is empty"))
+	}
+
+	if input.Limit <= 0 || input.Limit > maxSearchLimit {
+		input.Limit = defaultSearchLimit
+	}
+
+	resp, err := bsky.ActorSearchActors(ctx, p.GetClient(), "", int64(input.Limit), input.Query, "")
+	if err != nil {
+		p.logger.Error("failed to search users", "error", err)
+		return e.JSON(500, makeErrorJson("failed to search users"))
+	}
+
+	var usersText string
+	usersText += "<BEGIN USERS>\n"
+	for _, actor := range resp.Actors {
+		if p.isIgnored(actor.Did) {
+			continue
+		}
+
+		var displayName, description string
+		if actor.DisplayName != nil {
+			displayName = *actor.DisplayName
+		}
+		if actor.Description != nil {
+			description = strings.Join(strings.Fields(*actor.Description), " ")
+		}
+
+		usersText += fmt.Sprintf("<BEGIN USER>handle: @%s\ndid: %s\nname: %s\nbio: %s<END USER>\n", actor.Handle, actor.Did, displayName, description)
+	}
+	usersText += "<END USERS>"
+
+	return e.JSON(200, SearchUsersResponse{
+		Users: usersText,
+	})
+}
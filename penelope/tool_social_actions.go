@@ -0,0 +1,288 @@
+package penelope
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/lex/util"
+	"github.com/labstack/echo/v4"
+)
+
+type PostActionInput struct {
+	Uri string `json:"uri"`
+}
+
+type QuotePostInput struct {
+	Uri  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type ActorActionInput struct {
+	Actor string `json:"actor"`
+}
+
+type SocialActionResponse struct {
+	// Uri is the record that was created, or the existing record if the action had already been taken
+	Uri         string `json:"uri,omitempty"`
+	AlreadyDone bool   `json:"already_done"`
+}
+
+func (p *Penelope) socialActionError(e echo.Context, action string, err error) error {
+	if errors.Is(err, errSocialActionLimited) {
+		return e.JSON(429, makeErrorJson("daily "+action+" limit reached"))
+	}
+	return e.JSON(400, makeErrorJson(err.Error()))
+}
+
+func (p *Penelope) handleLike(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input PostActionInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	post, err := p.getPostView(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson(err.Error()))
+	}
+
+	if p.isIgnored(post.Author.Did) {
+		return e.JSON(400, makeErrorJson("cannot interact with this user"))
+	}
+
+	if post.Viewer != nil && post.Viewer.Like != nil {
+		return e.JSON(200, SocialActionResponse{Uri: *post.Viewer.Like, AlreadyDone: true})
+	}
+
+	if err := p.checkSocialAction(SocialActionLike); err != nil {
+		return p.socialActionError(e, SocialActionLike, err)
+	}
+
+	uri, err := p.createOwnRecord(ctx, "app.bsky.feed.like", &atproto.RepoCreateRecord_Input{
+		Record: &util.LexiconTypeDecoder{Val: &bsky.FeedLike{
+			Subject:   &atproto.RepoStrongRef{Uri: post.Uri, Cid: post.Cid},
+			CreatedAt: syntax.DatetimeNow().String(),
+		}},
+	})
+	if err != nil {
+		p.logger.Error("failed to like post", "uri", post.Uri, "error", err)
+		return e.JSON(500, makeErrorJson("failed to like post"))
+	}
+
+	p.recordSocialAction(SocialActionLike)
+
+	return e.JSON(200, SocialActionResponse{Uri: uri})
+}
+
+func (p *Penelope) handleUnlike(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input PostActionInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	post, err := p.getPostView(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson(err.Error()))
+	}
+
+	if post.Viewer == nil || post.Viewer.Like == nil {
+		return e.JSON(200, SocialActionResponse{AlreadyDone: true})
+	}
+
+	if err := p.checkSocialAction(SocialActionUnlike); err != nil {
+		return p.socialActionError(e, SocialActionUnlike, err)
+	}
+
+	if err := p.deleteOwnRecord(ctx, *post.Viewer.Like); err != nil {
+		p.logger.Error("failed to unlike post", "uri", post.Uri, "error", err)
+		return e.JSON(500, makeErrorJson("failed to unlike post"))
+	}
+
+	p.recordSocialAction(SocialActionUnlike)
+
+	return e.JSON(200, SocialActionResponse{Uri: *post.Viewer.Like})
+}
+
+func (p *Penelope) handleRepost(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input PostActionInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	post, err := p.getPostView(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson(err.Error()))
+	}
+
+	if p.isIgnored(post.Author.Did) {
+		return e.JSON(400, makeErrorJson("cannot interact with this user"))
+	}
+
+	if post.Viewer != nil && post.Viewer.Repost != nil {
+		return e.JSON(200, SocialActionResponse{Uri: *post.Viewer.Repost, AlreadyDone: true})
+	}
+
+	if err := p.checkSocialAction(SocialActionRepost); err != nil {
+		return p.socialActionError(e, SocialActionRepost, err)
+	}
+
+	uri, err := p.createOwnRecord(ctx, "app.bsky.feed.repost", &atproto.RepoCreateRecord_Input{
+		Record: &util.LexiconTypeDecoder{Val: &bsky.FeedRepost{
+			Subject:   &atproto.RepoStrongRef{Uri: post.Uri, Cid: post.Cid},
+			CreatedAt: syntax.DatetimeNow().String(),
+		}},
+	})
+	if err != nil {
+		p.logger.Error("failed to repost post", "uri", post.Uri, "error", err)
+		return e.JSON(500, makeErrorJson("failed to repost post"))
+	}
+
+	p.recordSocialAction(SocialActionRepost)
+
+	return e.JSON(200, SocialActionResponse{Uri: uri})
+}
+
+func (p *Penelope) handleQuotePost(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input QuotePostInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	if strings.TrimSpace(input.Text) == "" {
+		return e.JSON(400, makeErrorJson("text is empty"))
+	}
+
+	post, err := p.getPostView(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson(err.Error()))
+	}
+
+	if p.isIgnored(post.Author.Did) {
+		return e.JSON(400, makeErrorJson("cannot interact with this user"))
+	}
+
+	if post.Viewer != nil && post.Viewer.EmbeddingDisabled != nil && *post.Viewer.EmbeddingDisabled {
+		return e.JSON(400, makeErrorJson("the author has disabled quoting this post"))
+	}
+
+	if err := p.checkSocialAction(SocialActionQuote); err != nil {
+		return p.socialActionError(e, SocialActionQuote, err)
+	}
+
+	refs, err := p.createPostChainWithEmbed(ctx, nil, nil, input.Text, &bsky.FeedPost_Embed{
+		EmbedRecord: &bsky.EmbedRecord{
+			Record: &atproto.RepoStrongRef{Uri: post.Uri, Cid: post.Cid},
+		},
+	})
+	if err != nil {
+		p.logger.Error("failed to quote post", "uri", post.Uri, "error", err)
+		return e.JSON(500, makeErrorJson("failed to quote post"))
+	}
+
+	p.recordSocialAction(SocialActionQuote)
+
+	return e.JSON(200, SocialActionResponse{Uri: refs[0].Uri})
+}
+
+func (p *Penelope) handleFollow(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input ActorActionInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	did, err := p.resolveActor(ctx, input.Actor)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid actor"))
+	}
+
+	if did == p.botDid {
+		return e.JSON(400, makeErrorJson("cannot follow yourself"))
+	}
+
+	if p.isIgnored(did) {
+		return e.JSON(400, makeErrorJson("cannot interact with this user"))
+	}
+
+	profile, err := bsky.ActorGetProfile(ctx, p.GetClient(), did)
+	if err != nil {
+		p.logger.Error("failed to get profile", "did", did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get profile"))
+	}
+
+	if profile.Viewer != nil {
+		if profile.Viewer.Following != nil {
+			return e.JSON(200, SocialActionResponse{Uri: *profile.Viewer.Following, AlreadyDone: true})
+		}
+		if profile.Viewer.Blocking != nil || (profile.Viewer.BlockedBy != nil && *profile.Viewer.BlockedBy) {
+			return e.JSON(400, makeErrorJson("cannot follow this user"))
+		}
+	}
+
+	if err := p.checkSocialAction(SocialActionFollow); err != nil {
+		return p.socialActionError(e, SocialActionFollow, err)
+	}
+
+	uri, err := p.createOwnRecord(ctx, "app.bsky.graph.follow", &atproto.RepoCreateRecord_Input{
+		Record: &util.LexiconTypeDecoder{Val: &bsky.GraphFollow{
+			Subject:   did,
+			CreatedAt: syntax.DatetimeNow().String(),
+		}},
+	})
+	if err != nil {
+		p.logger.Error("failed to follow user", "did", did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to follow user"))
+	}
+
+	p.recordSocialAction(SocialActionFollow)
+
+	return e.JSON(200, SocialActionResponse{Uri: uri})
+}
+
+func (p *Penelope) handleUnfollow(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input ActorActionInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	did, err := p.resolveActor(ctx, input.Actor)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid actor"))
+	}
+
+	profile, err := bsky.ActorGetProfile(ctx, p.GetClient(), did)
+	if err != nil {
+		p.logger.Error("failed to get profile", "did", did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get profile"))
+	}
+
+	if profile.Viewer == nil || profile.Viewer.Following == nil {
+		return e.JSON(200, SocialActionResponse{AlreadyDone: true})
+	}
+
+	if err := p.checkSocialAction(SocialActionUnfollow); err != nil {
+		return p.socialActionError(e, SocialActionUnfollow, err)
+	}
+
+	if err := p.deleteOwnRecord(ctx, *profile.Viewer.Following); err != nil {
+		p.logger.Error("failed to unfollow user", "did", did, "error", err)
+		return e.JSON(500, makeErrorJson("failed to unfollow user"))
+	}
+
+	p.recordSocialAction(SocialActionUnfollow)
+
+	return e.JSON(200, SocialActionResponse{Uri: *profile.Viewer.Following})
+}
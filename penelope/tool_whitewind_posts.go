@@ -0,0 +1,312 @@
+package penelope
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/lex/util"
+	"github.com/labstack/echo/v4"
+)
+
+type WhitewindPostView struct {
+	Uri        string `json:"uri"`
+	Cid        string `json:"cid"`
+	Url        string `json:"url"`
+	Title      string `json:"title"`
+	Subtitle   string `json:"subtitle,omitempty"`
+	Visibility string `json:"visibility"`
+	CreatedAt  string `json:"createdAt"`
+	Content    string `json:"content,omitempty"`
+}
+
+func (p *Penelope) whitewindPostView(uri, cid string, rec *WhitewindRecord, withContent bool) (WhitewindPostView, error) {
+	aturi, err := syntax.ParseATURI(uri)
+	if err != nil {
+		return WhitewindPostView{}, err
+	}
+
+	view := WhitewindPostView{
+		Uri:        uri,
+		Cid:        cid,
+		Url:        whitewindUrl(p.botDid, aturi.RecordKey().String()),
+		Title:      rec.Title,
+		Subtitle:   rec.Subtitle,
+		Visibility: rec.Visibility,
+		CreatedAt:  rec.CreatedAt,
+	}
+	if withContent {
+		view.Content = rec.Content
+	}
+
+	return view, nil
+}
+
+// whitewindRkey returns the record key for one of the bot's whitewind entries. The entry may be given as an at-uri, a
+// whtwnd.com url, or a bare record key. Handles in at-uris and urls are resolved before checking that the entry is the
+// bot's.
+func (p *Penelope) whitewindRkey(ctx context.Context, entry string) (string, error) {
+	entry = strings.TrimSpace(entry)
+
+	switch {
+	case strings.HasPrefix(entry, "at://"):
+		aturi, err := syntax.ParseATURI(entry)
+		if err != nil {
+			return "", err
+		}
+		did, err := p.resolveActor(ctx, aturi.Authority().String())
+		if err != nil {
+			return "", err
+		}
+		if did != p.botDid {
+			return "", fmt.Errorf("entry is not in the bot's repo")
+		}
+		if aturi.Collection().String() != whitewindCollection {
+			return "", fmt.Errorf("uri is not a whitewind entry")
+		}
+		return aturi.RecordKey().String(), nil
+	case strings.HasPrefix(entry, "https://"):
+		u, err := url.Parse(entry)
+		if err != nil {
+			return "", err
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if u.Host != "whtwnd.com" || len(parts) != 2 {
+			return "", fmt.Errorf("url is not a whitewind entry")
+		}
+		// whtwnd.com urls use either the did or the handle of the author
+		did, err := p.resolveActor(ctx, parts[0])
+		if err != nil {
+			return "", err
+		}
+		if did != p.botDid {
+			return "", fmt.Errorf("entry is not in the bot's repo")
+		}
+		entry = parts[1]
+	}
+
+	rkey, err := syntax.ParseRecordKey(entry)
+	if err != nil {
+		return "", err
+	}
+
+	return rkey.String(), nil
+}
+
+func (p *Penelope) getWhitewindRecord(ctx context.Context, rkey string) (*atproto.RepoGetRecord_Output, *WhitewindRecord, error) {
+	out, err := atproto.RepoGetRecord(ctx, p.GetClient(), "", whitewindCollection, p.botDid, rkey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get record: %w", err)
+	}
+
+	if out.Value == nil {
+		return nil, nil, fmt.Errorf("record has no value")
+	}
+
+	rec, ok := out.Value.Val.(*WhitewindRecord)
+	if !ok {
+		return nil, nil, fmt.Errorf("record is not a whitewind entry")
+	}
+
+	return out, rec, nil
+}
+
+type ListWhitewindPostsInput struct {
+	Cursor string `json:"cursor"`
+	Limit  int64  `json:"limit"`
+}
+
+type ListWhitewindPostsResponse struct {
+	Posts  []WhitewindPostView `json:"posts"`
+	Cursor string              `json:"cursor,omitempty"`
+}
+
+func (p *Penelope) handleListWhitewindPosts(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input ListWhitewindPostsInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	if input.Limit <= 0 || input.Limit > 100 {
+		input.Limit = 25
+	}
+
+	out, err := atproto.RepoListRecords(ctx, p.GetClient(), whitewindCollection, input.Cursor, input.Limit, p.botDid, false)
+	if err != nil {
+		p.logger.Error("failed to list whitewind posts", "error", err)
+		return e.JSON(500, makeErrorJson("failed to list whitewind posts: "+err.Error()))
+	}
+
+	posts := []WhitewindPostView{}
+	for _, r := range out.Records {
+		if r.Value == nil {
+			continue
+		}
+		rec, ok := r.Value.Val.(*WhitewindRecord)
+		if !ok {
+			continue
+		}
+		view, err := p.whitewindPostView(r.Uri, r.Cid, rec, false)
+		if err != nil {
+			continue
+		}
+		posts = append(posts, view)
+	}
+
+	var cursor string
+	if out.Cursor != nil {
+		cursor = *out.Cursor
+	}
+
+	return e.JSON(200, ListWhitewindPostsResponse{
+		Posts:  posts,
+		Cursor: cursor,
+	})
+}
+
+type GetWhitewindPostInput struct {
+	Uri string `json:"uri"`
+}
+
+func (p *Penelope) handleGetWhitewindPost(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input GetWhitewindPostInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	rkey, err := p.whitewindRkey(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid whitewind post: "+err.Error()))
+	}
+
+	out, rec, err := p.getWhitewindRecord(ctx, rkey)
+	if err != nil {
+		p.logger.Error("failed to get whitewind post", "rkey", rkey, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get whitewind post: "+err.Error()))
+	}
+
+	var cid string
+	if out.Cid != nil {
+		cid = *out.Cid
+	}
+
+	view, err := p.whitewindPostView(out.Uri, cid, rec, true)
+	if err != nil {
+		return e.JSON(500, makeErrorJson("invalid whitewind post uri"))
+	}
+
+	return e.JSON(200, view)
+}
+
+type UpdateWhitewindPostInput struct {
+	Uri        string               `json:"uri"`
+	Title      *string              `json:"title"`
+	Subtitle   *string              `json:"subtitle"`
+	Text       *string              `json:"text"`
+	Visibility *string              `json:"visibility"`
+	Images     []LongformImageInput `json:"images"`
+}
+
+func (p *Penelope) handleUpdateWhitewindPost(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input UpdateWhitewindPostInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	rkey, err := p.whitewindRkey(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid whitewind post: "+err.Error()))
+	}
+
+	out, rec, err := p.getWhitewindRecord(ctx, rkey)
+	if err != nil {
+		p.logger.Error("failed to get whitewind post", "rkey", rkey, "error", err)
+		return e.JSON(500, makeErrorJson("failed to get whitewind post: "+err.Error()))
+	}
+
+	if input.Title != nil {
+		rec.Title = *input.Title
+	}
+	if input.Subtitle != nil {
+		rec.Subtitle = *input.Subtitle
+	}
+	if input.Text != nil {
+		rec.Content = cleanWhitewindContent(*input.Text)
+		if rec.Content == "" {
+			return e.JSON(400, makeErrorJson("content is empty"))
+		}
+	}
+	if input.Visibility != nil {
+		if !validWhitewindVisibility(*input.Visibility) {
+			return e.JSON(400, makeErrorJson("invalid visibility, must be one of public, url, or author"))
+		}
+		rec.Visibility = *input.Visibility
+	}
+
+	content, images, err := p.uploadLongformImages(ctx, rec.Content, input.Images)
+	if err != nil {
+		p.logger.Error("failed to upload whitewind images", "rkey", rkey, "error", err)
+		return e.JSON(500, makeErrorJson("failed to upload images: "+err.Error()))
+	}
+	rec.Content = content
+	rec.Blobs = append(rec.Blobs, whitewindBlobs(images)...)
+
+	res, err := atproto.RepoPutRecord(ctx, p.GetClient(), &atproto.RepoPutRecord_Input{
+		Collection: whitewindCollection,
+		Repo:       p.botDid,
+		Rkey:       rkey,
+		Record:     &util.LexiconTypeDecoder{Val: rec},
+		SwapRecord: out.Cid,
+	})
+	if err != nil {
+		p.logger.Error("failed to update whitewind post", "rkey", rkey, "error", err)
+		return e.JSON(500, makeErrorJson("failed to update whitewind post: "+err.Error()))
+	}
+
+	view, err := p.whitewindPostView(res.Uri, res.Cid, rec, false)
+	if err != nil {
+		return e.JSON(500, makeErrorJson("invalid whitewind post uri"))
+	}
+
+	return e.JSON(200, view)
+}
+
+type DeleteWhitewindPostInput struct {
+	Uri string `json:"uri"`
+}
+
+func (p *Penelope) handleDeleteWhitewindPost(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var input DeleteWhitewindPostInput
+	if err := e.Bind(&input); err != nil {
+		return e.JSON(400, makeErrorJson("failed to bind request"))
+	}
+
+	rkey, err := p.whitewindRkey(ctx, input.Uri)
+	if err != nil {
+		return e.JSON(400, makeErrorJson("invalid whitewind post: "+err.Error()))
+	}
+
+	if _, err := atproto.RepoDeleteRecord(ctx, p.GetClient(), &atproto.RepoDeleteRecord_Input{
+		Collection: whitewindCollection,
+		Repo:       p.botDid,
+		Rkey:       rkey,
+	}); err != nil {
+		p.logger.Error("failed to delete whitewind post", "rkey", rkey, "error", err)
+		return e.JSON(500, makeErrorJson("failed to delete whitewind post: "+err.Error()))
+	}
+
+	p.logger.Info("deleted whitewind post", "rkey", rkey)
+
+	return e.NoContent(200)
+}